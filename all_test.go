@@ -1,10 +1,14 @@
 package restcountries
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -38,13 +42,98 @@ func TestAllErrorUrl(t *testing.T) {
 
 	_, gotErr := testClient.All(AllOptions{})
 
-	wantErr := `Get "not%20a%20url/all?access_key=TEST_API_KEY&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/all?access_key=REDACTED&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
 	}
 }
 
+func TestAllContextCancelled(t *testing.T) {
+	testClient := New("TEST_API_KEY")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"TestName", "capital": "testCap"}]`)
+	}))
+	defer server.Close()
+	testClient.SetApiRoot(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, gotErr := testClient.AllContext(ctx, AllOptions{})
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Fatalf("got %v; want %v", gotErr, context.Canceled)
+	}
+}
+
+func TestAllBadRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"status": 400, "message": "Bad Request"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	result, gotErr := testClient.All(AllOptions{Fields: []string{"unknown"}})
+
+	var apiErr *APIError
+	if !errors.As(gotErr, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || errors.Is(gotErr, ErrNotFound) {
+		t.Fatalf("got %v, %v; want a 400 *APIError", result, gotErr)
+	}
+}
+
+func TestAllWithClient(t *testing.T) {
+	var gotUrl string
+	mockedClient := &ClientMock{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			gotUrl = req.URL.String()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(`[{"name":"TestName", "capital": "testCap"}]`)),
+			}, nil
+		},
+	}
+
+	testClient := NewWithClient("TEST_API_KEY", mockedClient)
+	testClient.SetApiRoot("http://example.com/v2")
+
+	result, err := testClient.All(AllOptions{})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "TestName" {
+		t.Fatalf("got %v; want TestName", result)
+	}
+
+	wantUrl := "http://example.com/v2/all?access_key=TEST_API_KEY&fields="
+	if gotUrl != wantUrl {
+		t.Fatalf("got url %s; want %s", gotUrl, wantUrl)
+	}
+}
+
+func TestAllTimeout(t *testing.T) {
+	testClient := New("TEST_API_KEY")
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+	testClient.SetApiRoot(server.URL)
+	testClient.SetTimeout(50 * time.Millisecond)
+
+	_, gotErr := testClient.All(AllOptions{})
+
+	if !errors.Is(gotErr, context.DeadlineExceeded) {
+		t.Fatalf("got %v; want %v", gotErr, context.DeadlineExceeded)
+	}
+}
+
 func TestAll(t *testing.T) {
 
 	testClient := New("TEST_API_KEY")
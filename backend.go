@@ -0,0 +1,248 @@
+package restcountries
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Endpoint identifies a kind of lookup, e.g. by name or by currency
+type Endpoint string
+
+// The lookups answered by the methods of RestCountries
+const (
+	EndpointAll          Endpoint = "all"
+	EndpointName         Endpoint = "name"
+	EndpointCapital      Endpoint = "capital"
+	EndpointCurrency     Endpoint = "currency"
+	EndpointLanguage     Endpoint = "language"
+	EndpointRegion       Endpoint = "region"
+	EndpointRegionalBloc Endpoint = "regionalbloc"
+	EndpointCallingCode  Endpoint = "callingcode"
+	EndpointCodes        Endpoint = "codes"
+
+	// EndpointQuery is a lookup of any path of the API, made with Query
+	EndpointQuery Endpoint = "query"
+)
+
+// Request describes a lookup independently of the API which answers it
+type Request struct {
+	Endpoint Endpoint
+	Term     string   // the search term, for every endpoint except EndpointAll and EndpointCodes
+	Codes    []string // the country codes, for EndpointCodes
+	FullText bool     // search for an exact match, for EndpointName
+	Fields   []string // the fields to return, or all fields when empty
+
+	Path   string     // the path under the API root, for EndpointQuery
+	Params url.Values // extra query parameters, for EndpointQuery
+}
+
+// validate checks the request has the search term its endpoint needs
+func (req Request) validate() error {
+	switch req.Endpoint {
+	case EndpointAll:
+		return nil
+	case EndpointCodes:
+		if len(req.Codes) == 0 {
+			return ErrEmptySearchTerm
+		}
+	case EndpointQuery:
+		if req.Path == "" {
+			return ErrEmptySearchTerm
+		}
+	default:
+		if req.Term == "" {
+			return ErrEmptySearchTerm
+		}
+	}
+
+	return nil
+}
+
+// Backend answers lookups, from an API or from data held locally
+// RestCountries and Offline are both backends, so either can be used with WithBackend
+type Backend interface {
+	Lookup(ctx context.Context, req Request) ([]Country, error)
+}
+
+// Provider translates lookups to the HTTP API of a country data provider and normalises its responses into Country
+// Countrylayer and RestCountriesV3 are the providers shipped with the package
+type Provider interface {
+	// BaseURL returns the default API root of the provider
+	BaseURL() string
+	// URL returns the url which answers req, under the API root root
+	URL(root string, apiKey string, req Request) (string, error)
+	// Decode decodes the body of a successful response into countries
+	Decode(content []byte) ([]Country, error)
+	// NotFound reports whether an error status from the API means that req matched no countries
+	NotFound(req Request, status int) bool
+}
+
+// WithProvider sends requests to the API of provider instead of Countrylayer
+// Unless WithBaseURL is also given, the client uses the default root of the provider
+func WithProvider(provider Provider) Option {
+	return func(r *RestCountries) {
+		if provider != nil {
+			r.provider = provider
+		}
+	}
+}
+
+// WithBackend answers every lookup with backend instead of an HTTP API, e.g. New("", WithBackend(NewOffline()))
+// The HTTP options of the client, such as the cache, retries and rate limit, don't apply to the backend
+func WithBackend(backend Backend) Option {
+	return func(r *RestCountries) {
+		r.backend = backend
+	}
+}
+
+// Lookup answers req with the backend set by WithBackend, or else with the HTTP API of the provider
+// With WithFailover, the fallbacks answer req when that fails
+// Every method of RestCountries is a Lookup, and Lookup makes RestCountries a Backend itself
+func (r *RestCountries) Lookup(ctx context.Context, req Request) ([]Country, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, end := r.startLookup(ctx, req)
+	countries, err := r.lookupFailover(ctx, req)
+	err = redactKey(err, r.apiKey)
+	end(len(countries), err)
+
+	return countries, err
+}
+
+// lookupFailover answers a validated req, with the fallbacks of WithFailover when there are any
+func (r *RestCountries) lookupFailover(ctx context.Context, req Request) ([]Country, error) {
+	if r.failover == nil {
+		return r.lookup(ctx, req)
+	}
+
+	countries, err := r.failover.lookup(ctx, req)
+	if err == nil && len(countries) == 0 && req.Endpoint != EndpointAll && r.notFoundError {
+		return nil, ErrNotFound
+	}
+	return countries, err
+}
+
+// lookup answers a validated req without failing over
+func (r *RestCountries) lookup(ctx context.Context, req Request) ([]Country, error) {
+	if r.backend != nil {
+		countries, err := r.backend.Lookup(ctx, req)
+		if err == nil && len(countries) == 0 && req.Endpoint != EndpointAll && r.notFoundError {
+			return nil, ErrNotFound
+		}
+		return countries, err
+	}
+
+	url, err := r.provider.URL(r.apiRoot, r.urlAPIKey(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.fetchCountries(ctx, url, req)
+}
+
+// Lookup answers req from the countries of the Offline, so it can be used with WithBackend
+func (o *Offline) Lookup(ctx context.Context, req Request) ([]Country, error) {
+	switch req.Endpoint {
+	case EndpointAll:
+		return o.AllContext(ctx, AllOptions{Fields: req.Fields})
+	case EndpointName:
+		return o.NameContext(ctx, NameOptions{Name: req.Term, FullText: req.FullText, Fields: req.Fields})
+	case EndpointCapital:
+		return o.CapitalContext(ctx, CapitalOptions{Capital: req.Term, Fields: req.Fields})
+	case EndpointCurrency:
+		return o.CurrencyContext(ctx, CurrencyOptions{Currency: req.Term, Fields: req.Fields})
+	case EndpointLanguage:
+		return o.LanguageContext(ctx, LanguageOptions{Language: req.Term, Fields: req.Fields})
+	case EndpointRegion:
+		return o.RegionContext(ctx, RegionOptions{Region: req.Term, Fields: req.Fields})
+	case EndpointRegionalBloc:
+		return o.RegionalBlocContext(ctx, RegionalBlocOptions{RegionalBloc: req.Term, Fields: req.Fields})
+	case EndpointCallingCode:
+		return o.CallingCodeContext(ctx, CallingCodeOptions{CallingCode: req.Term, Fields: req.Fields})
+	case EndpointCodes:
+		return o.CodesContext(ctx, CodesOptions{Codes: req.Codes, Fields: req.Fields})
+	}
+
+	return nil, ErrUnsupported
+}
+
+// endpointConfig configures an endpoint of the API of a provider
+type endpointConfig struct {
+	path     string // the path, to which the search term is appended
+	notFound []int  // the error statuses which mean the lookup matched no countries
+}
+
+var (
+	// notFoundStatuses mean a lookup matched no countries
+	notFoundStatuses = []int{http.StatusNotFound}
+
+	// invalidTermStatuses mean a lookup matched no countries: 404, or 400 for a search term the API rejects as invalid
+	// Elsewhere a 400 is a mistake in the request, e.g. an unknown field, so it stays an error
+	invalidTermStatuses = []int{http.StatusNotFound, http.StatusBadRequest}
+)
+
+// endpointURL returns the url answering req under root, from the endpoint table of a provider and the query params
+// The search term is appended to the path of the endpoint, except for EndpointQuery which has its own path and params
+func endpointURL(root string, endpoints map[Endpoint]endpointConfig, req Request, params url.Values) (string, error) {
+	config, ok := endpoints[req.Endpoint]
+	if !ok {
+		return "", ErrUnsupported
+	}
+
+	base, err := url.Parse(root)
+	if err != nil {
+		return "", err
+	}
+
+	if req.Endpoint == EndpointQuery {
+		base.Path += "/" + strings.TrimPrefix(req.Path, "/")
+	} else {
+		base.Path += config.path + req.Term // this encodes the user input properly with %20 for space and others
+	}
+
+	for name, values := range req.Params {
+		for _, value := range values {
+			params.Add(name, value)
+		}
+	}
+	base.RawQuery = params.Encode()
+
+	return base.String(), nil
+}
+
+// endpointNotFound reports whether status means req matched no countries, from the endpoint table of a provider
+func endpointNotFound(endpoints map[Endpoint]endpointConfig, req Request, status int) bool {
+	for _, notFound := range endpoints[req.Endpoint].notFound {
+		if status == notFound {
+			return true
+		}
+	}
+	return false
+}
+
+// urlAPIKey returns the API key to put in request urls, which is empty when it is sent in a header by WithAPIKeyHeader
+func (r *RestCountries) urlAPIKey() string {
+	if r.apiKeyHeader != "" {
+		return ""
+	}
+	return r.apiKey
+}
+
+// requestPath returns the path of rawurl relative to the API root, e.g. /name/France
+func requestPath(root string, rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return ""
+	}
+
+	rootURL, err := url.Parse(root)
+	if err != nil {
+		return u.Path
+	}
+
+	return strings.TrimPrefix(u.Path, strings.TrimSuffix(rootURL.Path, "/"))
+}
@@ -0,0 +1,135 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithBackendOffline(t *testing.T) {
+	testClient := New("", WithBackend(NewOffline()))
+
+	result, err := testClient.Codes(CodesOptions{Codes: []string{"EE"}, Fields: []string{"Name"}})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "Estonia" || result[0].Capital != "" {
+		t.Fatalf("got %v; want Estonia with only the name", result)
+	}
+
+	_, err = New("", WithBackend(NewOffline()), WithNotFoundError()).Name(NameOptions{Name: "Atlantis"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got err %v; want %v", err, ErrNotFound)
+	}
+}
+
+func TestWithBackendClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	// a client is a backend itself
+	inner := New("TEST_API_KEY", WithBaseURL(server.URL))
+	testClient := New("", WithBackend(inner))
+
+	result, err := testClient.Capital(CapitalOptions{Capital: "Paris"})
+	if err != nil || len(result) != 1 || result[0].Name != "France" {
+		t.Fatalf("got %v, %v; want France", result, err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		fmt.Fprintln(w, `[{"name":"Colombia"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	_, err := testClient.Lookup(context.Background(), Request{Endpoint: EndpointCodes, Codes: []string{"CO", "GB"}, Fields: []string{"Name"}})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	wantURL := "/alpha/?access_key=TEST_API_KEY&codes=CO%3BGB%3B&fields=name%3B"
+	if gotURL != wantURL {
+		t.Fatalf("got url %s; want %s", gotURL, wantURL)
+	}
+
+	if _, err := testClient.Lookup(context.Background(), Request{Endpoint: EndpointCodes}); !errors.Is(err, ErrEmptySearchTerm) {
+		t.Fatalf("got err %v; want %v", err, ErrEmptySearchTerm)
+	}
+
+	if _, err := testClient.Lookup(context.Background(), Request{Endpoint: "subregion", Term: "Caribbean"}); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("got err %v; want %v", err, ErrUnsupported)
+	}
+}
+
+func TestRequestPath(t *testing.T) {
+	tests := []struct {
+		root string
+		url  string
+		want string
+	}{
+		{root: "https://api.countrylayer.com/v2", url: "https://api.countrylayer.com/v2/name/United%20States?access_key=KEY", want: "/name/United States"},
+		{root: "http://127.0.0.1:1234", url: "http://127.0.0.1:1234/all?fields=", want: "/all"},
+		{root: "https://restcountries.com/v3.1/", url: "https://restcountries.com/v3.1/alpha?codes=CO", want: "/alpha"},
+	}
+
+	for _, test := range tests {
+		if got := requestPath(test.root, test.url); got != test.want {
+			t.Fatalf("got %s; want %s", got, test.want)
+		}
+	}
+}
+
+func TestWithProviderBaseURL(t *testing.T) {
+	if got := New("").apiRoot; got != "https://api.countrylayer.com/v2" {
+		t.Fatalf("got root %s; want the countrylayer root", got)
+	}
+
+	if got := New("", WithProvider(RestCountriesV3)).apiRoot; got != "https://restcountries.com/v3.1" {
+		t.Fatalf("got root %s; want the restcountries.com root", got)
+	}
+
+	if got := New("", WithBaseURL("http://localhost/v3.1"), WithProvider(RestCountriesV3)).apiRoot; got != "http://localhost/v3.1" {
+		t.Fatalf("got root %s; want the given root", got)
+	}
+}
+
+func TestNotFoundConsistent(t *testing.T) {
+	endpoints := []Endpoint{EndpointAll, EndpointName, EndpointCapital, EndpointCurrency, EndpointLanguage, EndpointRegion,
+		EndpointRegionalBloc, EndpointCallingCode, EndpointCodes, EndpointQuery}
+
+	// a 400 means an invalid search term only for these, and is an error elsewhere, e.g. for an unknown field
+	badRequestNotFound := map[Provider]map[Endpoint]bool{
+		Countrylayer:    {EndpointCurrency: true, EndpointCodes: true},
+		RestCountriesV3: {EndpointCodes: true},
+	}
+
+	for _, provider := range []Provider{Countrylayer, RestCountriesV3} {
+		for _, endpoint := range endpoints {
+			req := Request{Endpoint: endpoint}
+			if _, err := provider.URL("https://example.com", "", req); errors.Is(err, ErrUnsupported) {
+				continue
+			}
+
+			if !provider.NotFound(req, http.StatusNotFound) {
+				t.Errorf("%T %s: got 404 found; want not found", provider, endpoint)
+			}
+			if got, want := provider.NotFound(req, http.StatusBadRequest), badRequestNotFound[provider][endpoint]; got != want {
+				t.Errorf("%T %s: got 400 not found %v; want %v", provider, endpoint, got, want)
+			}
+			if provider.NotFound(req, http.StatusUnauthorized) {
+				t.Errorf("%T %s: got 401 not found; want an error", provider, endpoint)
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package restcountries
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cache stores raw API responses, keyed by the request URL without the API key
+// Implementations must be safe for concurrent use
+type Cache interface {
+	// Get returns the value stored for key, if there is one which hasn't expired
+	Get(key string) ([]byte, bool)
+	// Set stores the value for key, expiring it after ttl. A ttl of 0 means the value doesn't expire
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// ValidatorCache is a Cache which also keeps the HTTP validators of each response
+// When an entry has expired, the client sends a conditional request with the validators and reuses the entry if the API responds 304 Not Modified
+type ValidatorCache interface {
+	Cache
+	// GetStale returns the value and validators stored for key, even if the value has expired
+	GetStale(key string) ([]byte, Validators, bool)
+	// SetWithValidators stores the value and validators for key, expiring the value after ttl
+	SetWithValidators(key string, value []byte, ttl time.Duration, validators Validators)
+}
+
+// Validators are the HTTP validators of a response, used to revalidate it with a conditional request
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// validatorsFrom returns the validators from the response headers
+func validatorsFrom(header http.Header) Validators {
+	return Validators{
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+}
+
+// header returns the conditional request headers for the validators
+func (v Validators) header() http.Header {
+	header := http.Header{}
+	if v.ETag != "" {
+		header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("If-Modified-Since", v.LastModified)
+	}
+	return header
+}
+
+// response returns the validators as the response headers they come from
+func (v Validators) response() http.Header {
+	header := http.Header{}
+	if v.ETag != "" {
+		header.Set("ETag", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("Last-Modified", v.LastModified)
+	}
+	return header
+}
+
+// CacheMode controls how a single call uses the cache, see WithCacheMode
+type CacheMode int
+
+const (
+	// CacheDefault reads from the cache and stores fresh responses in it
+	CacheDefault CacheMode = iota
+	// CacheBypass neither reads from nor writes to the cache
+	CacheBypass
+	// CacheRefresh skips reading from the cache but stores the fresh response, replacing any cached one
+	CacheRefresh
+)
+
+type cacheModeKey struct{}
+
+// WithCache caches successful responses in cache for ttl, so repeated lookups don't use the network or API quota
+// A ttl of 0 means cached responses don't expire
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(r *RestCountries) {
+		r.cache = cache
+		r.cacheTTL = ttl
+	}
+}
+
+// WithCacheMode returns a copy of ctx which makes a call using it bypass or refresh the cache
+// e.g. client.AllContext(restcountries.WithCacheMode(ctx, restcountries.CacheRefresh), options)
+func WithCacheMode(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheModeKey{}, mode)
+}
+
+// cacheModeFrom returns the cache mode set on ctx with WithCacheMode
+func cacheModeFrom(ctx context.Context) CacheMode {
+	mode, _ := ctx.Value(cacheModeKey{}).(CacheMode)
+	return mode
+}
+
+// cacheKey returns the canonical form of the request url, without the access_key and with the query sorted
+func cacheKey(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	params := u.Query()
+	params.Del("access_key")
+	u.RawQuery = params.Encode()
+
+	return u.String()
+}
+
+// MemoryCache is an in-memory Cache which evicts the least recently used entry when it is full
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is the most recently used
+	now        func() time.Time
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an in-memory LRU cache holding up to maxEntries responses. 0 means no limit
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value stored for key, if there is one which hasn't expired
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if !entry.expires.IsZero() && !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set stores the value for key, expiring it after ttl. A ttl of 0 means the value doesn't expire
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*memoryCacheEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{key: key, value: value, expires: expires})
+
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries which haven't been removed yet
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,115 @@
+package restcountries
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheKey(t *testing.T) {
+	got := cacheKey("https://api.countrylayer.com/v2/name/United%20States?fullText=true&access_key=SECRET&fields=name%3B")
+	want := "https://api.countrylayer.com/v2/name/United%20States?fields=name%3B&fullText=true"
+
+	if got != want {
+		t.Fatalf("got %s; want %s", got, want)
+	}
+}
+
+func TestCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/name/Nowhere" {
+			fmt.Fprint(w, `{"status": 404, "message": "Not Found"}`)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(10)
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		result, err := testClient.Name(NameOptions{Name: "France"})
+		if err != nil || len(result) != 1 || result[0].Name != "France" {
+			t.Fatalf("got %v, %v; want France", result, err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("got %d calls; want 1", calls)
+	}
+
+	// bypass neither reads nor writes
+	if _, err := testClient.AllContext(WithCacheMode(context.Background(), CacheBypass), AllOptions{}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+	if calls != 2 || cache.Len() != 1 {
+		t.Fatalf("got %d calls and %d entries; want 2 calls and 1 entry", calls, cache.Len())
+	}
+
+	// refresh goes to the network and stores the response
+	if _, err := testClient.NameContext(WithCacheMode(context.Background(), CacheRefresh), NameOptions{Name: "France"}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+	if calls != 3 || cache.Len() != 1 {
+		t.Fatalf("got %d calls and %d entries; want 3 calls and 1 entry", calls, cache.Len())
+	}
+
+	// not found is never cached
+	for i := 0; i < 2; i++ {
+		if _, err := testClient.Name(NameOptions{Name: "Nowhere"}); err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+	}
+	if calls != 5 || cache.Len() != 1 {
+		t.Fatalf("got %d calls and %d entries; want 5 calls and 1 entry", calls, cache.Len())
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), 0)
+	cache.Get("a") // a is now the most recently used
+	cache.Set("c", []byte("3"), 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Fatalf("got b; want it evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Fatalf("got no %s; want it cached", key)
+		}
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewMemoryCache(0)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), 0)
+
+	now = now.Add(time.Hour)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("got a; want it expired")
+	}
+
+	if value, ok := cache.Get("b"); !ok || string(value) != "2" {
+		t.Fatalf("got %s, %v; want 2", value, ok)
+	}
+
+	if cache.Len() != 1 {
+		t.Fatalf("got len %d; want 1", cache.Len())
+	}
+}
@@ -0,0 +1,124 @@
+package restcountries
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker set with WithCircuitBreaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through, counting consecutive failures
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests with ErrCircuitOpen until the cooldown has passed
+	CircuitOpen
+	// CircuitHalfOpen lets a single trial request through, which closes the circuit if it succeeds or opens it again if it fails
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitBreaker stops requests to the API after threshold consecutive failures, for cooldown
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	trial     bool // set while the trial request of the half-open state is in flight
+	now       func() time.Time
+}
+
+// WithCircuitBreaker fails requests fast with ErrCircuitOpen after threshold consecutive failed requests, for cooldown
+// A network error, a timeout or a 5xx status is a failure, and each retry counts as a request. A 5xx status which the
+// provider says means no countries matched, e.g. the 500 of the Countrylayer codes lookup, isn't a failure
+// After the cooldown a single trial request is let through: if it succeeds the circuit closes, otherwise it opens for another cooldown
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(r *RestCountries) {
+		if threshold < 1 {
+			r.breaker = nil
+			return
+		}
+		r.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+			now:       time.Now,
+		}
+	}
+}
+
+// CircuitState returns the state of the circuit breaker set with WithCircuitBreaker, or CircuitClosed if there is none
+func (r *RestCountries) CircuitState() CircuitState {
+	if r.breaker == nil {
+		return CircuitClosed
+	}
+
+	r.breaker.mu.Lock()
+	defer r.breaker.mu.Unlock()
+	return r.breaker.currentState()
+}
+
+// currentState returns the state, which is half-open once the cooldown of an open circuit has passed
+func (b *circuitBreaker) currentState() CircuitState {
+	if b.state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.cooldown)) {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, taking the trial request of a half-open circuit
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState() {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.trial = true
+	}
+
+	return nil
+}
+
+// record reports the outcome of an allowed request
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// cancel hands back the trial request of a request which ended without an outcome, e.g. because its context was cancelled
+func (b *circuitBreaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
@@ -0,0 +1,184 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	var calls int32
+	var failing int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintln(w, `{"status": 502, "message": "Bad Gateway"}`)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute))
+	now := time.Now()
+	testClient.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := testClient.All(AllOptions{}); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: got err %v; want the API error", i, err)
+		}
+	}
+
+	if got := testClient.CircuitState(); got != CircuitOpen {
+		t.Fatalf("got state %v; want %v", got, CircuitOpen)
+	}
+
+	// an open circuit fails fast
+	if _, err := testClient.All(AllOptions{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got err %v; want %v", err, ErrCircuitOpen)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("got %d calls; want 2", got)
+	}
+
+	// a failed trial opens it again
+	now = now.Add(time.Minute)
+	if got := testClient.CircuitState(); got != CircuitHalfOpen {
+		t.Fatalf("got state %v; want %v", got, CircuitHalfOpen)
+	}
+	testClient.All(AllOptions{})
+	if got := testClient.CircuitState(); got != CircuitOpen {
+		t.Fatalf("got state %v; want %v", got, CircuitOpen)
+	}
+
+	// and a successful one closes it
+	now = now.Add(time.Minute)
+	atomic.StoreInt32(&failing, 0)
+	result, err := testClient.All(AllOptions{})
+	if err != nil || len(result) != 1 {
+		t.Fatalf("got %v, %v; want Estonia", result, err)
+	}
+	if got := testClient.CircuitState(); got != CircuitClosed {
+		t.Fatalf("got state %v; want %v", got, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerHalfOpenTrial(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: time.Minute, now: time.Now}
+	b.record(true)
+
+	b.openedAt = b.openedAt.Add(-time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("got err %v for the trial; want nil", err)
+	}
+	// only one trial is let through at a time
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got err %v during the trial; want %v", err, ErrCircuitOpen)
+	}
+
+	// a trial without an outcome hands its place to the next request
+	b.cancel()
+	if err := b.allow(); err != nil {
+		t.Fatalf("got err %v after a cancelled trial; want nil", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"status": 404, "message": "Not Found"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCircuitBreaker(1, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		testClient.Name(NameOptions{Name: "Atlantis"})
+	}
+	if got := testClient.CircuitState(); got != CircuitClosed {
+		t.Fatalf("got state %v; want %v", got, CircuitClosed)
+	}
+
+	// nor does a caller giving up count as a failure
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testClient.NameContext(ctx, NameOptions{Name: "Atlantis"})
+	if got := testClient.CircuitState(); got != CircuitClosed {
+		t.Fatalf("got state %v after a cancelled request; want %v", got, CircuitClosed)
+	}
+}
+
+func TestCircuitBreakerIgnoresNotFoundServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("codes"), "XX") {
+			// the Countrylayer API's answer when one of the codes doesn't match
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintln(w, `{"status": 500, "message": "Internal Server Error"}`)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"Colombia"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if _, err := testClient.Codes(CodesOptions{Codes: []string{"CO", "XX"}}); err != nil {
+			t.Fatalf("got err %v; want not found", err)
+		}
+	}
+	if got := testClient.CircuitState(); got != CircuitClosed {
+		t.Fatalf("got state %v; want %v", got, CircuitClosed)
+	}
+
+	result, err := testClient.Codes(CodesOptions{Codes: []string{"CO"}})
+	if err != nil || len(result) != 1 {
+		t.Fatalf("got %v, %v; want Colombia", result, err)
+	}
+}
+
+func TestCircuitBreakerStopsRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"status": 503, "message": "Service Unavailable"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCircuitBreaker(2, time.Minute),
+		WithRetry(RetryPolicy{MaxAttempts: 5, BaseBackoff: time.Millisecond}))
+
+	_, err := testClient.All(AllOptions{})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("got err %v; want %v", err, ErrCircuitOpen)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("got %d calls; want 2", got)
+	}
+}
+
+func TestCircuitStateString(t *testing.T) {
+	tests := []struct {
+		state CircuitState
+		want  string
+	}{
+		{CircuitClosed, "closed"},
+		{CircuitOpen, "open"},
+		{CircuitHalfOpen, "half-open"},
+		{CircuitState(9), "unknown"},
+	}
+
+	for _, test := range tests {
+		if got := test.state.String(); got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+		}
+	}
+}
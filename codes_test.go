@@ -39,7 +39,7 @@ func TestCodesErrorUrl(t *testing.T) {
 		Codes: []string{"CO"},
 	})
 
-	wantErr := `Get "not%20a%20url/alpha/?access_key=TEST_API_KEY&codes=CO%3B&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/alpha/?access_key=REDACTED&codes=CO%3B&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
@@ -0,0 +1,195 @@
+package restcountries
+
+import (
+	"encoding/json"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Currency is a currency used in a country
+type Currency struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+// String returns the code of the currency with its name and symbol, e.g. EUR (Euro, €)
+func (c Currency) String() string {
+	var details []string
+	for _, detail := range []string{c.Name, c.Symbol} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+
+	if len(details) == 0 {
+		return c.Code
+	}
+	return c.Code + " (" + strings.Join(details, ", ") + ")"
+}
+
+// Language is a language spoken in a country
+type Language struct {
+	Iso6391    string `json:"iso639_1"`
+	Iso6392    string `json:"iso639_2"`
+	Name       string `json:"name"`
+	NativeName string `json:"nativeName"`
+}
+
+// Is reports whether code is the ISO 639-1 or ISO 639-2 code of the language, ignoring case, e.g. et or est for Estonian
+func (l Language) Is(code string) bool {
+	return code != "" && (strings.EqualFold(l.Iso6391, code) || strings.EqualFold(l.Iso6392, code))
+}
+
+// RegionalBloc is a trade bloc or other regional organisation a country belongs to
+type RegionalBloc struct {
+	Acronym       string   `json:"acronym"`
+	Name          string   `json:"name"`
+	OtherAcronyms []string `json:"otherAcronyms"`
+	OtherNames    []string `json:"otherNames"`
+}
+
+// Is reports whether acronym is the acronym or one of the other acronyms of the bloc, ignoring case
+func (b RegionalBloc) Is(acronym string) bool {
+	if acronym == "" {
+		return false
+	}
+	if strings.EqualFold(b.Acronym, acronym) {
+		return true
+	}
+	for _, other := range b.OtherAcronyms {
+		if strings.EqualFold(other, acronym) {
+			return true
+		}
+	}
+	return false
+}
+
+// Translations are the names of a country in other languages, keyed by BCP 47 language tag, e.g. de or pt-BR
+// The br key of the API, which is Brazilian Portuguese rather than Breton, is read and written as pt-BR
+type Translations map[string]string
+
+// translationKey returns the map key for a translation key of the API, which is its canonical BCP 47 tag
+func translationKey(key string) string {
+	if key == "br" {
+		return "pt-BR"
+	}
+
+	tag, err := language.Parse(key)
+	if err != nil {
+		return key
+	}
+	return tag.String()
+}
+
+func (t *Translations) UnmarshalJSON(data []byte) error {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = nil
+	for key, name := range raw {
+		if name == "" {
+			continue
+		}
+		if *t == nil {
+			*t = make(Translations, len(raw))
+		}
+		(*t)[translationKey(key)] = name
+	}
+
+	return nil
+}
+
+func (t Translations) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]string, len(t))
+	for key, name := range t {
+		if key == "pt-BR" {
+			key = "br"
+		}
+		raw[key] = name
+	}
+
+	return json.Marshal(raw)
+}
+
+// Get returns the name of the country for a locale, e.g. de, de-AT or pt_BR, with the fallbacks of Lookup
+// It returns an empty string if the locale isn't a valid language tag or there is no translation for it
+func (t Translations) Get(locale string) string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if locale == "br" {
+		locale = "pt-BR"
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return ""
+	}
+
+	name, _ := t.Lookup(tag)
+	return name
+}
+
+// Lookup returns the translation for tag, falling back to less specific tags when there is none for tag itself
+// e.g. pt-BR falls back to pt, zh-Hant-TW to zh-Hant then zh, and zh-TW to the likely zh-Hant
+func (t Translations) Lookup(tag language.Tag) (string, bool) {
+	for _, key := range translationKeys(tag) {
+		if name, ok := t[key]; ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// translationKeys returns the keys to try for tag, from the most to the least specific
+func translationKeys(tag language.Tag) []string {
+	base, script, region := tag.Raw()
+	hasScript := script.String() != "Zzzz"
+	hasRegion := region.String() != "ZZ"
+
+	var tags []language.Tag
+	if hasScript && hasRegion {
+		tags = append(tags, mustCompose(base, script, region))
+	}
+	if hasScript {
+		tags = append(tags, mustCompose(base, script))
+	}
+	if hasRegion {
+		tags = append(tags, mustCompose(base, region))
+	}
+	if likely, confidence := tag.Script(); !hasScript && confidence != language.No {
+		tags = append(tags, mustCompose(base, likely))
+	}
+	tags = append(tags, mustCompose(base))
+
+	keys := []string{tag.String()}
+	for _, t := range tags {
+		if key := t.String(); key != keys[len(keys)-1] {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// mustCompose composes a tag from parts which came from a valid tag, so it can't fail
+func mustCompose(parts ...interface{}) language.Tag {
+	tag, _ := language.Compose(parts...)
+	return tag
+}
+
+// LocalizedName returns the name of the country in the language of tag, falling back to less specific tags as
+// Translations.Lookup does, and to the English name when there is no translation
+func (c Country) LocalizedName(tag language.Tag) string {
+	if base, _ := tag.Base(); base.String() == "en" {
+		return c.Name
+	}
+
+	if name, ok := c.Translations.Lookup(tag); ok {
+		return name
+	}
+	return c.Name
+}
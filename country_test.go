@@ -0,0 +1,194 @@
+package restcountries
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCurrencyString(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		want     string
+	}{
+		{Currency{Code: "EUR", Name: "Euro", Symbol: "€"}, "EUR (Euro, €)"},
+		{Currency{Code: "CHF", Name: "Swiss franc"}, "CHF (Swiss franc)"},
+		{Currency{Code: "XXX"}, "XXX"},
+	}
+
+	for _, test := range tests {
+		if got := test.currency.String(); got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+		}
+	}
+}
+
+func TestLanguageIs(t *testing.T) {
+	estonian := Language{Iso6391: "et", Iso6392: "est", Name: "Estonian", NativeName: "eesti"}
+
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"et", true},
+		{"EST", true},
+		{"Estonian", false},
+		{"fi", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := estonian.Is(test.code); got != test.want {
+			t.Errorf("%q: got %v; want %v", test.code, got, test.want)
+		}
+	}
+}
+
+func TestRegionalBlocIs(t *testing.T) {
+	bloc := RegionalBloc{Acronym: "EFTA", Name: "European Free Trade Association", OtherAcronyms: []string{"AELE"}}
+
+	tests := []struct {
+		acronym string
+		want    bool
+	}{
+		{"EFTA", true},
+		{"efta", true},
+		{"AELE", true},
+		{"EU", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if got := bloc.Is(test.acronym); got != test.want {
+			t.Errorf("%q: got %v; want %v", test.acronym, got, test.want)
+		}
+	}
+}
+
+func TestTranslationsGet(t *testing.T) {
+	translations := Translations{"de": "Estland", "pt": "Estónia", "pt-BR": "Estônia", "fa": "استونی", "zh-Hant": "愛沙尼亞", "zh": "爱沙尼亚"}
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "Estland"},
+		{"de-AT", "Estland"},
+		{"DE_ch", "Estland"},
+		{"pt", "Estónia"},
+		{"pt-PT", "Estónia"},
+		{"pt-BR", "Estônia"},
+		{"pt_br", "Estônia"},
+		{"br", "Estônia"},
+		{"fa", "استونی"},
+		{"zh-Hant-TW", "愛沙尼亞"},
+		{"zh-TW", "愛沙尼亞"},
+		{"zh-CN", "爱沙尼亚"},
+		{"zh-Hans", "爱沙尼亚"},
+		{"fr", ""},
+		{"sv", ""},
+		{"", ""},
+		{"not a tag", ""},
+	}
+
+	for _, test := range tests {
+		if got := translations.Get(test.locale); got != test.want {
+			t.Errorf("%q: got %q; want %q", test.locale, got, test.want)
+		}
+	}
+}
+
+func TestCountryJSON(t *testing.T) {
+	// the named types decode and encode the same JSON as before
+	const input = `{"name":"Estonia","currencies":[{"code":"EUR","name":"Euro","symbol":"€"}],` +
+		`"languages":[{"iso639_1":"et","iso639_2":"est","name":"Estonian","nativeName":"eesti"}],` +
+		`"translations":{"de":"Estland","es":"Estonia","fr":"Estonie","ja":"エストニア","it":"Estonia","br":"Estônia","pt":"Estónia","nl":"Estland","hr":"Estonija","fa":"استونی"},` +
+		`"regionalBlocs":[{"acronym":"EU","name":"European Union","otherAcronyms":[],"otherNames":[]}]}`
+
+	var country Country
+	if err := json.Unmarshal([]byte(input), &country); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Country{
+		Name:          "Estonia",
+		Currencies:    []Currency{{Code: "EUR", Name: "Euro", Symbol: "€"}},
+		Languages:     []Language{{Iso6391: "et", Iso6392: "est", Name: "Estonian", NativeName: "eesti"}},
+		Translations:  Translations{"de": "Estland", "es": "Estonia", "fr": "Estonie", "ja": "エストニア", "it": "Estonia", "pt-BR": "Estônia", "pt": "Estónia", "nl": "Estland", "hr": "Estonija", "fa": "استونی"},
+		RegionalBlocs: []RegionalBloc{{Acronym: "EU", Name: "European Union", OtherAcronyms: []string{}, OtherNames: []string{}}},
+	}
+	if !reflect.DeepEqual(country, want) {
+		t.Fatalf("got %+v; want %+v", country, want)
+	}
+
+	output, err := json.Marshal(country)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, wantJSON map[string]interface{}
+	json.Unmarshal(output, &got)
+	json.Unmarshal([]byte(input), &wantJSON)
+	for _, key := range []string{"currencies", "languages", "translations", "regionalBlocs"} {
+		if !reflect.DeepEqual(got[key], wantJSON[key]) {
+			t.Errorf("%s: got %v; want %v", key, got[key], wantJSON[key])
+		}
+	}
+}
+
+func TestTranslationsJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Translations
+	}{
+		{`{"de": "Estland", "br": "Estônia", "ru": "Эстония", "fi": ""}`, Translations{"de": "Estland", "pt-BR": "Estônia", "ru": "Эстония"}},
+		{`{"zh-hant": "愛沙尼亞", "sr_Latn": "Estonija"}`, Translations{"zh-Hant": "愛沙尼亞", "sr-Latn": "Estonija"}},
+		{`{}`, nil},
+		{`null`, nil},
+	}
+
+	for _, test := range tests {
+		var got Translations
+		if err := json.Unmarshal([]byte(test.input), &got); err != nil {
+			t.Fatalf("%s: got err %v", test.input, err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v; want %v", test.input, got, test.want)
+		}
+	}
+
+	output, err := json.Marshal(Translations{"pt-BR": "Estônia", "ru": "Эстония"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"br":"Estônia","ru":"Эстония"}`; string(output) != want {
+		t.Errorf("got %s; want %s", output, want)
+	}
+}
+
+func TestLocalizedName(t *testing.T) {
+	country := Country{Name: "Estonia", Translations: Translations{"de": "Estland", "pt": "Estónia", "pt-BR": "Estônia", "zh": "爱沙尼亚"}}
+
+	tests := []struct {
+		tag  language.Tag
+		want string
+	}{
+		{language.German, "Estland"},
+		{language.MustParse("de-AT"), "Estland"},
+		{language.BrazilianPortuguese, "Estônia"},
+		{language.EuropeanPortuguese, "Estónia"},
+		{language.TraditionalChinese, "爱沙尼亚"},
+		{language.English, "Estonia"},
+		{language.BritishEnglish, "Estonia"},
+		{language.Swedish, "Estonia"},
+		{language.Und, "Estonia"},
+	}
+
+	for _, test := range tests {
+		if got := country.LocalizedName(test.tag); got != test.want {
+			t.Errorf("%s: got %q; want %q", test.tag, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package restcountries
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Countrylayer is the provider for the Countrylayer API, formerly restcountries.eu, which is used by default
+var Countrylayer Provider = countrylayer{}
+
+type countrylayer struct{}
+
+// countrylayerEndpoints are the endpoints of the API
+var countrylayerEndpoints = map[Endpoint]endpointConfig{
+	EndpointAll:          {path: "/all", notFound: notFoundStatuses},
+	EndpointName:         {path: "/name/", notFound: notFoundStatuses},
+	EndpointCapital:      {path: "/capital/", notFound: notFoundStatuses},
+	EndpointCurrency:     {path: "/currency/", notFound: invalidTermStatuses},
+	EndpointLanguage:     {path: "/lang/", notFound: notFoundStatuses},
+	EndpointRegion:       {path: "/region/", notFound: notFoundStatuses},
+	EndpointRegionalBloc: {path: "/regionalbloc/", notFound: notFoundStatuses},
+	EndpointCallingCode:  {path: "/callingcode/", notFound: notFoundStatuses},
+	// the api returns a 400 for a single code which doesn't match a country, or a 500 for a list of codes where one or more do not match
+	EndpointCodes: {path: "/alpha/", notFound: append([]int{http.StatusInternalServerError}, invalidTermStatuses...)},
+	EndpointQuery: {notFound: notFoundStatuses},
+}
+
+func (countrylayer) BaseURL() string {
+	return "https://api.countrylayer.com/v2"
+}
+
+func (countrylayer) URL(root string, apiKey string, req Request) (string, error) {
+	params := url.Values{}
+	if apiKey != "" {
+		params.Add("access_key", apiKey)
+	}
+	params.Add("fields", processFields(req.Fields))
+	if req.FullText {
+		params.Add("fullText", "true")
+	}
+	if req.Endpoint == EndpointCodes {
+		params.Add("codes", processCodes(req.Codes))
+	}
+
+	return endpointURL(root, countrylayerEndpoints, req, params)
+}
+
+func (countrylayer) Decode(content []byte) ([]Country, error) {
+	var countries []Country
+	err := json.Unmarshal(content, &countries)
+	return countries, err
+}
+
+func (countrylayer) NotFound(req Request, status int) bool {
+	return endpointNotFound(countrylayerEndpoints, req, status)
+}
@@ -0,0 +1,311 @@
+package restcountries
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// CountryV3 represents a country in the schema of the restcountries.com v3.1 API
+// It has more data than Country, e.g. official names, maps, car signs and postal code formats
+// Use Country to normalise it, and CountryV3FromCountry to convert a Country
+type CountryV3 struct {
+	Name         NameV3                 `json:"name"`
+	TLD          []string               `json:"tld"`
+	CCA2         string                 `json:"cca2"`
+	CCN3         string                 `json:"ccn3"`
+	CCA3         string                 `json:"cca3"`
+	CIOC         string                 `json:"cioc"`
+	FIFA         string                 `json:"fifa"`
+	Independent  bool                   `json:"independent"`
+	Status       string                 `json:"status"`
+	UNMember     bool                   `json:"unMember"`
+	Currencies   map[string]CurrencyV3  `json:"currencies"`
+	IDD          IDDV3                  `json:"idd"`
+	Capital      []string               `json:"capital"`
+	CapitalInfo  CapitalInfoV3          `json:"capitalInfo"`
+	AltSpellings []string               `json:"altSpellings"`
+	Region       string                 `json:"region"`
+	Subregion    string                 `json:"subregion"`
+	Continents   []string               `json:"continents"`
+	Languages    map[string]string      `json:"languages"`
+	Translations map[string]LocalNameV3 `json:"translations"`
+	Latlng       []float64              `json:"latlng"`
+	Landlocked   bool                   `json:"landlocked"`
+	Borders      []string               `json:"borders"`
+	Area         float64                `json:"area"`
+	Demonyms     map[string]DemonymV3   `json:"demonyms"`
+	Flag         string                 `json:"flag"`
+	Flags        ImagesV3               `json:"flags"`
+	CoatOfArms   ImagesV3               `json:"coatOfArms"`
+	Maps         MapsV3                 `json:"maps"`
+	Population   int                    `json:"population"`
+	Gini         map[string]float64     `json:"gini"`
+	Car          CarV3                  `json:"car"`
+	Timezones    []string               `json:"timezones"`
+	StartOfWeek  string                 `json:"startOfWeek"`
+	PostalCode   PostalCodeV3           `json:"postalCode"`
+}
+
+// NameV3 is the name of a country in the v3.1 schema, with its native names keyed by ISO 639-3 language code
+type NameV3 struct {
+	Common     string                 `json:"common"`
+	Official   string                 `json:"official"`
+	NativeName map[string]LocalNameV3 `json:"nativeName"`
+}
+
+// LocalNameV3 is the name of a country in one language, used for native names and translations
+type LocalNameV3 struct {
+	Official string `json:"official"`
+	Common   string `json:"common"`
+}
+
+// CurrencyV3 is a currency in the v3.1 schema, keyed by its ISO 4217 code
+type CurrencyV3 struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+// IDDV3 is the international dialling code of a country: each root and suffix pair is a calling code, e.g. +3 and 72
+type IDDV3 struct {
+	Root     string   `json:"root"`
+	Suffixes []string `json:"suffixes"`
+}
+
+// CapitalInfoV3 is the location of the capital city
+type CapitalInfoV3 struct {
+	Latlng []float64 `json:"latlng"`
+}
+
+// DemonymV3 is the female and male demonym in one language
+type DemonymV3 struct {
+	F string `json:"f"`
+	M string `json:"m"`
+}
+
+// ImagesV3 are the URLs of an image, used for the flag and the coat of arms
+type ImagesV3 struct {
+	PNG string `json:"png"`
+	SVG string `json:"svg"`
+	Alt string `json:"alt,omitempty"`
+}
+
+// MapsV3 are links to the country on map services
+type MapsV3 struct {
+	GoogleMaps     string `json:"googleMaps"`
+	OpenStreetMaps string `json:"openStreetMaps"`
+}
+
+// CarV3 is the international vehicle registration codes and the side of the road driven on
+type CarV3 struct {
+	Signs []string `json:"signs"`
+	Side  string   `json:"side"`
+}
+
+// PostalCodeV3 is the format of postal codes, as a pattern and a regular expression
+type PostalCodeV3 struct {
+	Format string `json:"format"`
+	Regex  string `json:"regex"`
+}
+
+// translationCodeV3 returns the BCP 47 key of Translations for a translation key of the v3.1 schema, an ISO 639-3 code
+// It returns an empty string for Breton, whose key br means Brazilian Portuguese in Translations
+func translationCodeV3(code string) string {
+	if code == "per" {
+		return "fa"
+	}
+
+	base, err := language.ParseBase(code)
+	if err != nil || base.String() == "br" {
+		return ""
+	}
+	return base.String()
+}
+
+// Country normalises the country into a Country
+// Calling codes are the root and suffix together when there is one suffix, or else the root, e.g. 1 for North America
+// Currencies and languages are sorted by code, and languages have an ISO 639-1 code when there is one
+// Translations are keyed by the BCP 47 tag of their language, e.g. deu by de
+func (c CountryV3) Country() Country {
+	country := Country{
+		Name:           c.Name.Common,
+		TopLevelDomain: c.TLD,
+		Alpha2Code:     c.CCA2,
+		Alpha3Code:     c.CCA3,
+		AltSpellings:   c.AltSpellings,
+		Region:         c.Region,
+		Subregion:      c.Subregion,
+		Population:     c.Population,
+		Latlng:         c.Latlng,
+		Area:           c.Area,
+		Timezones:      c.Timezones,
+		Borders:        c.Borders,
+		NumericCode:    c.CCN3,
+		Flag:           c.Flags.SVG,
+		Cioc:           c.CIOC,
+	}
+
+	if len(c.Capital) > 0 {
+		country.Capital = c.Capital[0]
+	}
+
+	root := strings.TrimPrefix(c.IDD.Root, "+")
+	if len(c.IDD.Suffixes) == 1 {
+		country.CallingCodes = []string{root + c.IDD.Suffixes[0]}
+	} else if root != "" {
+		country.CallingCodes = []string{root}
+	}
+
+	if demonym, ok := c.Demonyms["eng"]; ok {
+		country.Demonym = demonym.M
+	}
+
+	// the gini index is given per year, of which the latest is used
+	for _, year := range slices.Sorted(maps.Keys(c.Gini)) {
+		country.Gini = c.Gini[year]
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(c.Name.NativeName)) {
+		country.NativeName = c.Name.NativeName[code].Common
+		break
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(c.Currencies)) {
+		currency := c.Currencies[code]
+		country.Currencies = append(country.Currencies, Currency{Code: code, Name: currency.Name, Symbol: currency.Symbol})
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(c.Languages)) {
+		lang := Language{Iso6392: code, Name: c.Languages[code]}
+		if base, err := language.ParseBase(code); err == nil && len(base.String()) == 2 {
+			lang.Iso6391 = base.String()
+		}
+		country.Languages = append(country.Languages, lang)
+	}
+
+	for code, translation := range c.Translations {
+		if key := translationCodeV3(code); key != "" && translation.Common != "" {
+			if country.Translations == nil {
+				country.Translations = make(Translations, len(c.Translations))
+			}
+			country.Translations[key] = translation.Common
+		}
+	}
+
+	return country
+}
+
+// CountryV3FromCountry converts a Country into the v3.1 schema
+// Data the v3.1 schema can't represent is lost, so CountryV3FromCountry(c).Country() differs from c in:
+//   - RegionalBlocs, which the schema doesn't have
+//   - the native names of languages, and the order of currencies and languages, which are keyed by code
+//   - translations for a region or script, e.g. pt-BR, as the schema keys them by language
+//   - calling codes, when there are several of them, which become their common prefix, e.g. 18 for 1809 and 1829
+//
+// The Gini coefficient, which has no year in a Country, is keyed by an empty year
+func CountryV3FromCountry(c Country) CountryV3 {
+	country := CountryV3{
+		Name:         NameV3{Common: c.Name},
+		TLD:          c.TopLevelDomain,
+		CCA2:         c.Alpha2Code,
+		CCN3:         c.NumericCode,
+		CCA3:         c.Alpha3Code,
+		CIOC:         c.Cioc,
+		AltSpellings: c.AltSpellings,
+		Region:       c.Region,
+		Subregion:    c.Subregion,
+		Latlng:       c.Latlng,
+		Borders:      c.Borders,
+		Area:         c.Area,
+		Flags:        ImagesV3{SVG: c.Flag},
+		Population:   c.Population,
+		Timezones:    c.Timezones,
+	}
+
+	if c.Capital != "" {
+		country.Capital = []string{c.Capital}
+	}
+
+	if c.NativeName != "" {
+		code := "und"
+		if len(c.Languages) > 0 && c.Languages[0].Iso6392 != "" {
+			code = c.Languages[0].Iso6392
+		}
+		country.Name.NativeName = map[string]LocalNameV3{code: {Common: c.NativeName}}
+	}
+
+	if c.Gini > 0 {
+		country.Gini = map[string]float64{"": c.Gini}
+	}
+
+	if c.Demonym != "" {
+		country.Demonyms = map[string]DemonymV3{"eng": {F: c.Demonym, M: c.Demonym}}
+	}
+
+	country.IDD = iddFromCallingCodes(c.CallingCodes)
+
+	if len(c.Currencies) > 0 {
+		country.Currencies = make(map[string]CurrencyV3, len(c.Currencies))
+		for _, currency := range c.Currencies {
+			country.Currencies[currency.Code] = CurrencyV3{Name: currency.Name, Symbol: currency.Symbol}
+		}
+	}
+
+	if len(c.Languages) > 0 {
+		country.Languages = make(map[string]string, len(c.Languages))
+		for _, language := range c.Languages {
+			code := language.Iso6392
+			if code == "" {
+				code = language.Iso6391
+			}
+			country.Languages[code] = language.Name
+		}
+	}
+
+	// the v3.1 schema keys translations by language, so those for a region or script, such as pt-BR, are lost
+	for key, name := range c.Translations {
+		base, err := language.ParseBase(key)
+		if err != nil || base.String() != key {
+			continue
+		}
+		code := base.ISO3()
+		if code == "fas" {
+			code = "per"
+		}
+		if country.Translations == nil {
+			country.Translations = make(map[string]LocalNameV3)
+		}
+		country.Translations[code] = LocalNameV3{Common: name}
+	}
+
+	return country
+}
+
+// iddFromCallingCodes returns the international dialling code for calling codes, with their common prefix as the root
+func iddFromCallingCodes(codes []string) IDDV3 {
+	if len(codes) == 0 {
+		return IDDV3{}
+	}
+
+	prefix := codes[0]
+	for _, code := range codes[1:] {
+		for !strings.HasPrefix(code, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	if len(codes) == 1 {
+		return IDDV3{Root: "+" + prefix}
+	}
+
+	idd := IDDV3{Root: "+" + prefix}
+	for _, code := range codes {
+		idd.Suffixes = append(idd.Suffixes, strings.TrimPrefix(code, prefix))
+	}
+	sort.Strings(idd.Suffixes)
+
+	return idd
+}
@@ -0,0 +1,149 @@
+package restcountries
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCountryV3Country(t *testing.T) {
+	var v3 []CountryV3
+	if err := json.Unmarshal([]byte(restCountriesV3Estonia), &v3); err != nil {
+		t.Fatal(err)
+	}
+
+	got := v3[0].Country()
+
+	if got.Name != "Estonia" || got.NativeName != "Eesti" || got.Capital != "Tallinn" {
+		t.Errorf("got names %q, %q, %q; want Estonia, Eesti, Tallinn", got.Name, got.NativeName, got.Capital)
+	}
+	if !reflect.DeepEqual(got.CallingCodes, []string{"372"}) {
+		t.Errorf("got calling codes %v; want [372]", got.CallingCodes)
+	}
+	if got.Gini != 30.3 {
+		t.Errorf("got gini %v; want the latest, 30.3", got.Gini)
+	}
+	if got.Translations["de"] != "Estland" || got.Translations["pt"] != "Estónia" || got.Translations.Get("pt-BR") != "Estónia" {
+		t.Errorf("got translations %+v", got.Translations)
+	}
+	if len(got.Languages) != 1 || got.Languages[0].Iso6392 != "est" || got.Languages[0].Name != "Estonian" {
+		t.Errorf("got languages %+v; want est Estonian", got.Languages)
+	}
+}
+
+func TestCountryV3IDD(t *testing.T) {
+	tests := []struct {
+		name  string
+		idd   IDDV3
+		codes []string
+	}{
+		{"none", IDDV3{}, nil},
+		{"root only", IDDV3{Root: "+7"}, []string{"7"}},
+		{"one suffix", IDDV3{Root: "+3", Suffixes: []string{"72"}}, []string{"372"}},
+		{"many suffixes", IDDV3{Root: "+1", Suffixes: []string{"201", "202"}}, []string{"1"}},
+	}
+
+	for _, test := range tests {
+		got := CountryV3{IDD: test.idd}.Country().CallingCodes
+		if !reflect.DeepEqual(got, test.codes) {
+			t.Errorf("%s: got %v; want %v", test.name, got, test.codes)
+		}
+	}
+}
+
+func TestCountryV3FromCountry(t *testing.T) {
+	// a country with only data the v3.1 schema can represent survives the round trip, JSON included
+	country := Country{
+		Name:           "Estonia",
+		TopLevelDomain: []string{".ee"},
+		Alpha2Code:     "EE",
+		Alpha3Code:     "EST",
+		CallingCodes:   []string{"372"},
+		Capital:        "Tallinn",
+		AltSpellings:   []string{"EE", "Eesti"},
+		Region:         "Europe",
+		Subregion:      "Northern Europe",
+		Population:     1331057,
+		Latlng:         []float64{59, 26},
+		Demonym:        "Estonian",
+		Area:           45227,
+		Gini:           30.4,
+		Timezones:      []string{"UTC+02:00"},
+		Borders:        []string{"LVA", "RUS"},
+		NativeName:     "Eesti",
+		NumericCode:    "233",
+		Currencies:     []Currency{{Code: "EUR", Name: "Euro", Symbol: "€"}},
+		Languages:      []Language{{Iso6391: "et", Iso6392: "est", Name: "Estonian"}},
+		Translations:   Translations{"de": "Estland", "fr": "Estonie", "fa": "استونی"},
+		Flag:           "https://flagcdn.com/ee.svg",
+		Cioc:           "EST",
+	}
+
+	content, err := json.Marshal(CountryV3FromCountry(country))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded CountryV3
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := decoded.Country(); !reflect.DeepEqual(got, country) {
+		t.Errorf("got %+v; want %+v", got, country)
+	}
+
+	// the data of every country of the snapshot which the schema represents is kept
+	for _, country := range NewOffline().countries {
+		got := CountryV3FromCountry(country).Country()
+		if got.Name != country.Name || got.NativeName != country.NativeName || got.Capital != country.Capital ||
+			got.Alpha3Code != country.Alpha3Code || len(got.Languages) != len(country.Languages) ||
+			len(got.Currencies) != len(country.Currencies) {
+			t.Errorf("%s: got %+v; want %+v", country.Alpha3Code, got, country)
+		}
+	}
+}
+
+func TestCountryV3FromCountryLoss(t *testing.T) {
+	got := CountryV3FromCountry(Country{
+		CallingCodes:  []string{"1809", "1829"},
+		Currencies:    []Currency{{Code: "USD"}, {Code: "EUR"}},
+		Languages:     []Language{{Iso6391: "sv", Iso6392: "swe", Name: "Swedish", NativeName: "svenska"}, {Iso6391: "fi", Iso6392: "fin", Name: "Finnish"}},
+		Translations:  Translations{"pt": "Estónia", "pt-BR": "Estônia"},
+		RegionalBlocs: []RegionalBloc{{Acronym: "EU", Name: "European Union"}},
+	}).Country()
+
+	if !reflect.DeepEqual(got.CallingCodes, []string{"18"}) {
+		t.Errorf("got calling codes %v; want their common prefix", got.CallingCodes)
+	}
+	if want := []Currency{{Code: "EUR"}, {Code: "USD"}}; !reflect.DeepEqual(got.Currencies, want) {
+		t.Errorf("got currencies %+v; want %+v", got.Currencies, want)
+	}
+	want := []Language{{Iso6391: "fi", Iso6392: "fin", Name: "Finnish"}, {Iso6391: "sv", Iso6392: "swe", Name: "Swedish"}}
+	if !reflect.DeepEqual(got.Languages, want) {
+		t.Errorf("got languages %+v; want %+v", got.Languages, want)
+	}
+	if !reflect.DeepEqual(got.Translations, Translations{"pt": "Estónia"}) {
+		t.Errorf("got translations %v; want only pt", got.Translations)
+	}
+	if got.RegionalBlocs != nil {
+		t.Errorf("got regional blocs %v; want none", got.RegionalBlocs)
+	}
+}
+
+func TestCountryV3FromCountryCallingCodes(t *testing.T) {
+	tests := []struct {
+		codes []string
+		idd   IDDV3
+	}{
+		{nil, IDDV3{}},
+		{[]string{"372"}, IDDV3{Root: "+372"}},
+		{[]string{"1809", "1829", "1849"}, IDDV3{Root: "+18", Suffixes: []string{"09", "29", "49"}}},
+	}
+
+	for _, test := range tests {
+		got := CountryV3FromCountry(Country{CallingCodes: test.codes}).IDD
+		if !reflect.DeepEqual(got, test.idd) {
+			t.Errorf("%v: got %+v; want %+v", test.codes, got, test.idd)
+		}
+	}
+}
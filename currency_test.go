@@ -39,7 +39,7 @@ func TestCurrencyErrorUrl(t *testing.T) {
 		Currency: "GBP",
 	})
 
-	wantErr := `Get "not%20a%20url/currency/GBP?access_key=TEST_API_KEY&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/currency/GBP?access_key=REDACTED&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
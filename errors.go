@@ -0,0 +1,78 @@
+package restcountries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	// ErrNotFound is matched by an *APIError for a search which found no countries
+	// It is only returned when the client is created with WithNotFoundError, otherwise an empty slice is returned
+	ErrNotFound = errors.New("Not Found")
+
+	// ErrEmptySearchTerm is returned when a search method is called without a search term
+	ErrEmptySearchTerm = errors.New("Search term is empty")
+
+	// ErrUnsupported is returned for a lookup which the provider or backend can't answer
+	ErrUnsupported = errors.New("lookup not supported by the provider")
+
+	// ErrInvalidAPIKey is matched by an *APIError for a missing, invalid or inactive API key
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	// ErrRateLimited is matched by an *APIError when the request or monthly usage limit of the plan was reached
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrCircuitOpen is returned without a request when the circuit breaker set with WithCircuitBreaker is open
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+)
+
+// APIError is returned when the API responds with an error instead of countries
+// Use errors.As to inspect it, or errors.Is with ErrNotFound, ErrInvalidAPIKey and ErrRateLimited to classify it
+type APIError struct {
+	StatusCode int    // the status from the error body, or the HTTP status when the body has none
+	Code       int    // the countrylayer error code, e.g. 101 for an invalid access key, if any
+	Type       string // the countrylayer error type, e.g. invalid_access_key, if any
+	Message    string // the error message from the API
+	Path       string // the request path, e.g. /name/France. The query, which holds the API key, is not included
+	Body       []byte // the raw response body
+
+	notFound bool // set when the status means the search found no countries for the endpoint
+}
+
+// Error returns the message from the API
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("API error %d for %s", e.StatusCode, e.Path)
+}
+
+// Is reports whether the error matches one of the sentinel errors ErrNotFound, ErrInvalidAPIKey or ErrRateLimited
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.notFound || e.StatusCode == http.StatusNotFound || e.Code == http.StatusNotFound
+	case ErrInvalidAPIKey:
+		return e.StatusCode == http.StatusUnauthorized || e.Code == 101 || e.Type == "invalid_access_key" || e.Type == "missing_access_key" || e.Type == "inactive_user"
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.Code == 104 || e.Code == 106 || e.Type == "usage_limit_reached" || e.Type == "rate_limit_reached"
+	}
+
+	return false
+}
+
+// apiError is the error body returned by the API
+// restcountries.eu style errors have a status and message, countrylayer errors have success false and an error object
+type apiError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Success *bool  `json:"success"`
+	Error   *struct {
+		Code int    `json:"code"`
+		Type string `json:"type"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
@@ -0,0 +1,128 @@
+package restcountries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+
+	tests := []struct {
+		response   string
+		httpStatus int
+		options    []Option
+		wantIs     error
+		wantStatus int
+		wantCode   int
+		wantMsg    string
+	}{
+		{
+			// not found is an error when requested
+			response:   `{"status": 404, "message": "Not Found"}`,
+			httpStatus: http.StatusNotFound,
+			options:    []Option{WithNotFoundError()},
+			wantIs:     ErrNotFound,
+			wantStatus: 404,
+			wantMsg:    "Not Found",
+		},
+		{
+			// countrylayer invalid key
+			response:   `{"success": false, "error": {"code": 101, "type": "invalid_access_key", "info": "You have not supplied a valid API Access Key."}}`,
+			httpStatus: http.StatusOK,
+			wantIs:     ErrInvalidAPIKey,
+			wantStatus: 200,
+			wantCode:   101,
+			wantMsg:    "You have not supplied a valid API Access Key.",
+		},
+		{
+			// countrylayer usage limit
+			response:   `{"success": false, "error": {"code": 104, "type": "usage_limit_reached", "info": "Your monthly usage limit has been reached."}}`,
+			httpStatus: http.StatusOK,
+			wantIs:     ErrRateLimited,
+			wantStatus: 200,
+			wantCode:   104,
+			wantMsg:    "Your monthly usage limit has been reached.",
+		},
+		{
+			// rate limited by HTTP status
+			response:   `{"status": 429, "message": "Too Many Requests"}`,
+			httpStatus: http.StatusTooManyRequests,
+			wantIs:     ErrRateLimited,
+			wantStatus: 429,
+			wantMsg:    "Too Many Requests",
+		},
+	}
+
+	for _, test := range tests {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.httpStatus)
+			fmt.Fprint(w, test.response)
+		}))
+		defer server.Close()
+
+		testClient := New("TEST_API_KEY", append(test.options, WithBaseURL(server.URL))...)
+
+		_, err := testClient.Name(NameOptions{Name: "France"})
+
+		if !errors.Is(err, test.wantIs) {
+			t.Fatalf("want err matching %v, got: %v", test.wantIs, err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("want *APIError, got: %T", err)
+		}
+
+		if apiErr.StatusCode != test.wantStatus || apiErr.Code != test.wantCode || apiErr.Message != test.wantMsg {
+			t.Fatalf("want status %d code %d message %s, got: %+v", test.wantStatus, test.wantCode, test.wantMsg, apiErr)
+		}
+
+		if apiErr.Path != "/name/France" {
+			t.Fatalf("want path /name/France, got: %s", apiErr.Path)
+		}
+
+		if string(apiErr.Body) != test.response {
+			t.Fatalf("want body %s, got: %s", test.response, apiErr.Body)
+		}
+	}
+}
+
+func TestErrorsNotFoundStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": 500, "message": "Internal Server Error"}`)
+	}))
+	defer server.Close()
+
+	// a 500 from Codes means one of the codes didn't match
+	_, err := New("TEST_API_KEY", WithBaseURL(server.URL), WithNotFoundError()).Codes(CodesOptions{Codes: []string{"CO", "XX"}})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want err matching %v, got: %v", ErrNotFound, err)
+	}
+
+	// a 500 from Name is a genuine error
+	_, err = New("TEST_API_KEY", WithBaseURL(server.URL), WithNotFoundError()).Name(NameOptions{Name: "France"})
+	if err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("want err not matching %v, got: %v", ErrNotFound, err)
+	}
+}
+
+func TestErrorsEmptySearchTerm(t *testing.T) {
+	_, err := New("TEST_API_KEY").Capital(CapitalOptions{})
+
+	if !errors.Is(err, ErrEmptySearchTerm) {
+		t.Fatalf("want err %v, got: %v", ErrEmptySearchTerm, err)
+	}
+}
+
+func TestAPIErrorWithoutMessage(t *testing.T) {
+	err := &APIError{StatusCode: 502, Path: "/all"}
+
+	want := "API error 502 for /all"
+	if err.Error() != want {
+		t.Fatalf("want %s, got: %s", want, err.Error())
+	}
+}
@@ -0,0 +1,132 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BackendHealth reports the health of one backend of the failover list set with WithFailover
+type BackendHealth struct {
+	Healthy   bool      // false while the backend is skipped after a failure
+	Failures  int       // the number of failures since the last success
+	LastError error     // the error of the last failure, or nil
+	RetryAt   time.Time // when an unhealthy backend is tried again
+}
+
+// failover answers lookups with the first healthy backend of an ordered list, the first being the client's own API
+type failover struct {
+	mu       sync.Mutex
+	backends []Backend
+	health   []BackendHealth
+	cooldown time.Duration
+	now      func() time.Time
+}
+
+// backendFunc adapts a lookup function to the Backend interface
+type backendFunc func(ctx context.Context, req Request) ([]Country, error)
+
+func (f backendFunc) Lookup(ctx context.Context, req Request) ([]Country, error) {
+	return f(ctx, req)
+}
+
+// WithFailover answers a lookup with the fallbacks, in order, when the client's own API fails with a network error or a 5xx status
+// The fallbacks can be clients for other API roots or providers, e.g. New(key, WithBaseURL(mirror)), with NewOffline() as the last resort
+// A backend which fails is skipped for cooldown, unless every backend is unhealthy. A backend which doesn't support a lookup
+// (ErrUnsupported) passes it to the next one without being marked unhealthy
+func WithFailover(cooldown time.Duration, fallbacks ...Backend) Option {
+	return func(r *RestCountries) {
+		if len(fallbacks) == 0 {
+			r.failover = nil
+			return
+		}
+		r.failover = &failover{
+			backends: append([]Backend{backendFunc(r.lookup)}, fallbacks...),
+			health:   make([]BackendHealth, len(fallbacks)+1),
+			cooldown: cooldown,
+			now:      time.Now,
+		}
+		for i := range r.failover.health {
+			r.failover.health[i].Healthy = true
+		}
+	}
+}
+
+// FailoverHealth returns the health of the backends set with WithFailover, starting with the client's own API
+// It returns nil if there is no failover
+func (r *RestCountries) FailoverHealth() []BackendHealth {
+	if r.failover == nil {
+		return nil
+	}
+
+	r.failover.mu.Lock()
+	defer r.failover.mu.Unlock()
+	return append([]BackendHealth(nil), r.failover.health...)
+}
+
+// lookup tries the healthy backends in order, then the unhealthy ones if all the healthy ones failed
+func (f *failover) lookup(ctx context.Context, req Request) ([]Country, error) {
+	f.mu.Lock()
+	now := f.now()
+	var healthy, unhealthy []int
+	for i := range f.backends {
+		if f.health[i].Healthy || !now.Before(f.health[i].RetryAt) {
+			healthy = append(healthy, i)
+		} else {
+			unhealthy = append(unhealthy, i)
+		}
+	}
+	f.mu.Unlock()
+
+	var err error
+	for _, i := range append(healthy, unhealthy...) {
+		var countries []Country
+		countries, err = f.backends[i].Lookup(ctx, req)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil || !failoverError(err) {
+			f.succeeded(i)
+			return countries, err
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			f.failed(i, err)
+		}
+	}
+
+	return nil, err
+}
+
+func (f *failover) succeeded(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.health[i] = BackendHealth{Healthy: true}
+}
+
+func (f *failover) failed(i int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.health[i].Healthy = false
+	f.health[i].Failures++
+	f.health[i].LastError = err
+	f.health[i].RetryAt = f.now().Add(f.cooldown)
+}
+
+// failoverError reports whether err should pass a lookup to the next backend: a network error, a 5xx status,
+// an undecodable response or an unsupported lookup, but not an answer from the API such as not found
+func failoverError(err error) bool {
+	if errors.Is(err, ErrUnsupported) {
+		return true
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrEmptySearchTerm) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+package restcountries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithFailover(t *testing.T) {
+	var primaryCalls, mirrorCalls int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryCalls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&mirrorCalls, 1)
+		fmt.Fprintln(w, `[{"name":"Mirrorland"}]`)
+	}))
+	defer mirror.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(primary.URL), WithFailover(time.Minute, New("TEST_API_KEY", WithBaseURL(mirror.URL))))
+	now := time.Now()
+	testClient.failover.now = func() time.Time { return now }
+
+	result, err := testClient.All(AllOptions{})
+	if err != nil || len(result) != 1 || result[0].Name != "Mirrorland" {
+		t.Fatalf("got %v, %v; want Mirrorland", result, err)
+	}
+
+	health := testClient.FailoverHealth()
+	if len(health) != 2 || health[0].Healthy || health[0].Failures != 1 || !health[1].Healthy {
+		t.Fatalf("got health %+v; want the primary unhealthy and the mirror healthy", health)
+	}
+	if !health[0].RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("got retry at %v; want %v", health[0].RetryAt, now.Add(time.Minute))
+	}
+
+	// the primary is skipped during the cooldown
+	testClient.All(AllOptions{})
+	if got := atomic.LoadInt32(&primaryCalls); got != 1 {
+		t.Errorf("got %d calls to the primary; want 1", got)
+	}
+
+	// and tried again after it
+	now = now.Add(time.Minute)
+	testClient.All(AllOptions{})
+	if got := atomic.LoadInt32(&primaryCalls); got != 2 {
+		t.Errorf("got %d calls to the primary; want 2", got)
+	}
+	if got := atomic.LoadInt32(&mirrorCalls); got != 3 {
+		t.Errorf("got %d calls to the mirror; want 3", got)
+	}
+}
+
+func TestWithFailoverErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		failover bool
+	}{
+		{"server error", http.StatusInternalServerError, true},
+		{"bad gateway", http.StatusBadGateway, true},
+		{"not found", http.StatusNotFound, false},
+		{"unauthorized", http.StatusUnauthorized, false},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			fmt.Fprintf(w, `{"status": %d, "message": "error"}`, test.status)
+		}))
+
+		testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithFailover(time.Minute, NewOffline()))
+		result, err := testClient.Name(NameOptions{Name: "Estonia", FullText: true})
+
+		if test.failover && (err != nil || len(result) != 1 || result[0].Name != "Estonia") {
+			t.Errorf("%s: got %v, %v; want Estonia from the offline fallback", test.name, result, err)
+		}
+		if !test.failover && len(result) != 0 {
+			t.Errorf("%s: got %v; want the answer of the API", test.name, result)
+		}
+
+		server.Close()
+	}
+}
+
+func TestWithFailoverNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithFailover(time.Minute, NewOffline()))
+
+	result, err := testClient.Name(NameOptions{Name: "Estonia", FullText: true})
+	if err != nil || len(result) != 1 {
+		t.Fatalf("got %v, %v; want Estonia from the offline fallback", result, err)
+	}
+	if health := testClient.FailoverHealth(); health[0].LastError == nil {
+		t.Errorf("got no last error for the primary")
+	}
+}
+
+func TestWithFailoverUnsupported(t *testing.T) {
+	// the v3.1 API has no regional bloc lookup, so the offline fallback answers it without marking the API unhealthy
+	testClient := New("", WithProvider(RestCountriesV3), WithFailover(time.Minute, NewOffline()))
+
+	result, err := testClient.RegionalBloc(RegionalBlocOptions{RegionalBloc: "EU"})
+	if err != nil || len(result) == 0 {
+		t.Fatalf("got %v, %v; want the EU members", result, err)
+	}
+	if health := testClient.FailoverHealth(); !health[0].Healthy {
+		t.Errorf("got the primary unhealthy; want healthy")
+	}
+}
+
+func TestWithFailoverAllUnhealthy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, `{"status": 500, "message": "Internal Server Error"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithFailover(time.Minute, New("TEST_API_KEY", WithBaseURL(server.URL))))
+
+	for i := 0; i < 2; i++ {
+		// every backend is tried even when all are unhealthy, returning the last error
+		_, err := testClient.All(AllOptions{})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("got err %v; want a 500 APIError", err)
+		}
+	}
+
+	for i, health := range testClient.FailoverHealth() {
+		if health.Healthy || health.Failures != 2 {
+			t.Errorf("backend %d: got %+v; want 2 failures", i, health)
+		}
+	}
+}
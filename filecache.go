@@ -0,0 +1,239 @@
+package restcountries
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileCache is a ValidatorCache which stores each response as a JSON file in a directory
+// It lets short-lived processes, such as CLI tools and serverless functions, reuse responses fetched by earlier runs
+// Files are written to a temporary file and renamed into place, so processes sharing the directory never see a partial entry
+type FileCache struct {
+	dir    string
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// fileCacheEntry is the content of a cache file
+type fileCacheEntry struct {
+	Key        string          `json:"key"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	ExpiresAt  time.Time       `json:"expiresAt"`
+	Validators Validators      `json:"validators"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// fileCacheExt is the extension of the cache files, so Purge doesn't remove anything else in the directory
+const fileCacheExt = ".json"
+
+// fileCacheTmpPattern is the pattern of the temporary files written before they are renamed into place
+const fileCacheTmpPattern = "entry-*.tmp"
+
+// fileCacheTmpMaxAge is the age after which a temporary file was left by a process which stopped while writing it,
+// rather than being written by a process sharing the directory
+const fileCacheTmpMaxAge = time.Hour
+
+// NewFileCache creates a file cache in dir, creating the directory if needed
+// An empty dir means go-restcountries in the user cache directory, see os.UserCacheDir
+// maxAge, when above 0, is the longest an entry is used after it was fetched, whatever the ttl it was stored with
+func NewFileCache(dir string, maxAge time.Duration) (*FileCache, error) {
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(userDir, "go-restcountries")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &FileCache{
+		dir:    dir,
+		maxAge: maxAge,
+		now:    time.Now,
+	}, nil
+}
+
+// Dir returns the directory holding the cache files
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+// Get returns the value stored for key, if there is one which hasn't expired
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	entry, ok := c.read(key)
+	if !ok || c.expired(entry) {
+		return nil, false
+	}
+
+	return entry.Body, true
+}
+
+// GetStale returns the value and validators stored for key, even if the value has expired
+func (c *FileCache) GetStale(key string) ([]byte, Validators, bool) {
+	entry, ok := c.read(key)
+	if !ok {
+		return nil, Validators{}, false
+	}
+
+	return entry.Body, entry.Validators, true
+}
+
+// Set stores the value for key, expiring it after ttl. A ttl of 0 means the value only expires by the max age
+// The value must be JSON. Errors writing the file are ignored, leaving the cache unchanged
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	c.SetWithValidators(key, value, ttl, Validators{})
+}
+
+// SetWithValidators stores the value and validators for key, expiring the value after ttl
+func (c *FileCache) SetWithValidators(key string, value []byte, ttl time.Duration, validators Validators) {
+	now := c.now()
+	entry := fileCacheEntry{
+		Key:        key,
+		FetchedAt:  now,
+		Validators: validators,
+		Body:       value,
+	}
+	if ttl > 0 {
+		entry.ExpiresAt = now.Add(ttl)
+	}
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	c.write(key, content)
+}
+
+// Purge removes every entry from the cache, and the temporary files left by processes which stopped while writing one
+func (c *FileCache) Purge() error {
+	return c.purge(func(fileCacheEntry, bool) bool {
+		return true
+	})
+}
+
+// PurgeExpired removes the entries which have expired, and any which can't be read, and the temporary files left by
+// processes which stopped while writing one
+func (c *FileCache) PurgeExpired() error {
+	return c.purge(func(entry fileCacheEntry, ok bool) bool {
+		return !ok || c.expired(entry)
+	})
+}
+
+// purge removes the cache files for which remove returns true
+func (c *FileCache) purge(remove func(entry fileCacheEntry, ok bool) bool) error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() {
+			continue
+		}
+
+		if leftover, _ := filepath.Match(fileCacheTmpPattern, name); leftover {
+			if err := c.purgeTmp(file); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !strings.HasSuffix(name, fileCacheExt) {
+			continue
+		}
+
+		entry, ok := readFileCacheEntry(filepath.Join(c.dir, name))
+		if !remove(entry, ok) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purgeTmp removes a temporary file older than fileCacheTmpMaxAge. A newer one may still be being written
+func (c *FileCache) purgeTmp(file os.DirEntry) error {
+	info, err := file.Info()
+	if err != nil || c.now().Sub(info.ModTime()) < fileCacheTmpMaxAge {
+		return nil
+	}
+
+	if err := os.Remove(filepath.Join(c.dir, file.Name())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// expired reports whether the entry is past its ttl or the max age
+func (c *FileCache) expired(entry fileCacheEntry) bool {
+	now := c.now()
+	if !entry.ExpiresAt.IsZero() && !now.Before(entry.ExpiresAt) {
+		return true
+	}
+
+	return c.maxAge > 0 && now.Sub(entry.FetchedAt) >= c.maxAge
+}
+
+// path returns the file path for key. The key is hashed, as it is a URL which can't be used as a file name
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+fileCacheExt)
+}
+
+// read returns the entry for key, if its file exists and is valid
+func (c *FileCache) read(key string) (fileCacheEntry, bool) {
+	entry, ok := readFileCacheEntry(c.path(key))
+	if !ok || entry.Key != key {
+		return fileCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// write atomically replaces the file for key with content
+func (c *FileCache) write(key string, content []byte) {
+	tmp, err := os.CreateTemp(c.dir, fileCacheTmpPattern)
+	if err != nil {
+		return
+	}
+
+	_, writeErr := tmp.Write(content)
+	closeErr := tmp.Close()
+	if writeErr != nil || closeErr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// readFileCacheEntry reads and decodes a cache file
+func readFileCacheEntry(path string) (fileCacheEntry, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fileCacheEntry{}, false
+	}
+
+	var entry fileCacheEntry
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return fileCacheEntry{}, false
+	}
+
+	return entry, true
+}
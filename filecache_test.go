@@ -0,0 +1,193 @@
+package restcountries
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatalf("got an entry; want none")
+	}
+
+	cache.Set("a", []byte(`[{"name":"France"}]`), time.Minute)
+
+	got, ok := cache.Get("a")
+	if !ok || string(got) != `[{"name":"France"}]` {
+		t.Fatalf("got %s, %v; want the stored value", got, ok)
+	}
+
+	// another instance sharing the directory sees the entry
+	other, _ := NewFileCache(cache.Dir(), 0)
+	if _, ok := other.Get("a"); !ok {
+		t.Fatalf("got no entry from a second cache; want it shared")
+	}
+
+	// a value which isn't JSON can't be stored
+	cache.Set("b", []byte(`not json`), time.Minute)
+	if _, ok := cache.Get("b"); ok {
+		t.Fatalf("got an entry for invalid JSON; want none")
+	}
+}
+
+func TestFileCacheExpiry(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache, _ := NewFileCache(t.TempDir(), time.Hour)
+	cache.now = func() time.Time { return now }
+
+	cache.SetWithValidators("ttl", []byte(`[]`), time.Minute, Validators{ETag: `"v1"`})
+	cache.Set("forever", []byte(`[]`), 0)
+
+	now = now.Add(2 * time.Minute)
+
+	if _, ok := cache.Get("ttl"); ok {
+		t.Fatalf("got ttl; want it expired")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Fatalf("got no forever; want it cached")
+	}
+
+	// a stale entry is still available for revalidation
+	if _, validators, ok := cache.GetStale("ttl"); !ok || validators.ETag != `"v1"` {
+		t.Fatalf("got %v, %v; want the stale entry", validators, ok)
+	}
+
+	// the max age expires entries without a ttl
+	now = now.Add(time.Hour)
+	if _, ok := cache.Get("forever"); ok {
+		t.Fatalf("got forever; want it past the max age")
+	}
+}
+
+func TestFileCachePurge(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	cache, _ := NewFileCache(dir, 0)
+	cache.now = func() time.Time { return now }
+
+	cache.Set("old", []byte(`[]`), time.Minute)
+	cache.Set("new", []byte(`[]`), time.Hour)
+	os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte(`{`), 0600)
+	os.WriteFile(filepath.Join(dir, "other.txt"), []byte(`keep`), 0600)
+
+	now = now.Add(2 * time.Minute)
+
+	if err := cache.PurgeExpired(); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if _, _, ok := cache.GetStale("old"); ok {
+		t.Fatalf("got old; want it purged")
+	}
+	if _, ok := cache.Get("new"); !ok {
+		t.Fatalf("got no new; want it kept")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "corrupt.json")); !os.IsNotExist(err) {
+		t.Fatalf("got corrupt file; want it purged")
+	}
+
+	if err := cache.Purge(); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 || files[0].Name() != "other.txt" {
+		t.Fatalf("got files %v; want only other.txt", files)
+	}
+}
+
+func TestFileCachePurgeTmp(t *testing.T) {
+	dir := t.TempDir()
+	cache, _ := NewFileCache(dir, 0)
+
+	// a process which stopped while writing left a temporary file, while another is writing one now
+	leftover := filepath.Join(dir, "entry-123.tmp")
+	writing := filepath.Join(dir, "entry-456.tmp")
+	os.WriteFile(leftover, []byte(`{"key":`), 0600)
+	os.WriteFile(writing, []byte(`{"key":`), 0600)
+	old := time.Now().Add(-2 * fileCacheTmpMaxAge)
+	os.Chtimes(leftover, old, old)
+
+	for _, purge := range []func() error{cache.PurgeExpired, cache.Purge} {
+		if err := purge(); err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Fatalf("got the leftover temporary file; want it purged")
+		}
+		if _, err := os.Stat(writing); err != nil {
+			t.Fatalf("got err %v; want the temporary file being written kept", err)
+		}
+
+		os.WriteFile(leftover, []byte(`{"key":`), 0600)
+		os.Chtimes(leftover, old, old)
+	}
+}
+
+func TestFileCacheConcurrentWrites(t *testing.T) {
+	cache, _ := NewFileCache(t.TempDir(), 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cache.Set("key", []byte(fmt.Sprintf(`[{"name":"Country %d"}]`, i)), 0)
+		}(i)
+	}
+	wg.Wait()
+
+	if _, ok := cache.Get("key"); !ok {
+		t.Fatalf("got no entry; want one complete entry")
+	}
+
+	files, _ := os.ReadDir(cache.Dir())
+	if len(files) != 1 {
+		t.Fatalf("got %d files; want 1 with no temporary files left", len(files))
+	}
+}
+
+func TestFileCacheRevalidate(t *testing.T) {
+	var calls, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	cache, _ := NewFileCache(t.TempDir(), 0)
+	cache.now = func() time.Time { return now }
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		result, err := testClient.Name(NameOptions{Name: "France"})
+		if err != nil || len(result) != 1 || result[0].Name != "France" {
+			t.Fatalf("got %v, %v; want France", result, err)
+		}
+		now = now.Add(2 * time.Minute)
+	}
+
+	if calls != 3 || notModified != 2 {
+		t.Fatalf("got %d calls and %d not modified; want 3 and 2", calls, notModified)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/chriscross0/go-restcountries/v2
 
-go 1.16
+go 1.23.0
+
+require golang.org/x/text v0.28.0
@@ -1,26 +1,162 @@
 package restcountries
 
 import (
-	"io/ioutil"
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
-// getUrlContent takes a url and http client (for mock testing) and makes a GET request, returning the response text and error
-func getUrlContent(url string, myClient httpClient) (string, error) {
-	req, _ := http.NewRequest("GET", url, nil)
+// response is an HTTP response whose body has been read into content, or which is streamed from body
+type response struct {
+	content   []byte
+	body      io.ReadCloser // the unread body of a response from openUrl, which the caller must close
+	status    int
+	header    http.Header
+	countries []Country // the countries decoded from the body as it arrived, when decoded is set
+	decoded   bool
+}
+
+// getUrlContent takes a url, optional request headers and http client (for mock testing) and makes a GET request, returning the response and error
+// The request is bound to ctx, so cancelling ctx aborts both the connection and the body read
+// It is used when the raw body is needed, e.g. to cache it. Otherwise the body is decoded as it arrives, see fetchDecoded
+func getUrlContent(ctx context.Context, url string, header http.Header, myClient Doer) (*response, error) {
+	resp, err := openUrl(ctx, url, header, myClient)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.body.Close()
+
+	body, readErr := io.ReadAll(resp.body)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	resp.content, resp.body = body, nil
+	return resp, nil
+}
+
+// openUrl makes a GET request like getUrlContent, but leaves the body unread so it can be decoded as it arrives
+func openUrl(ctx context.Context, url string, header http.Header, myClient Doer) (*response, error) {
+	req, reqErr := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if reqErr != nil {
+		return nil, redactError(reqErr)
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
 
 	resp, respErr := myClient.Do(req)
 
 	if respErr != nil {
-		return "", respErr
+		return nil, redactError(respErr)
 	}
 
-	defer resp.Body.Close()
+	return &response{
+		body:   resp.Body,
+		status: resp.StatusCode,
+		header: resp.Header,
+	}, nil
+}
 
-	body, readErr := ioutil.ReadAll(resp.Body)
-	if readErr != nil {
-		return "", readErr
+// cancelBody is the body of a streamed response, which cancels the context of its request when it is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// firstJSONByte returns the first byte of content which isn't JSON whitespace, or 0 if there is none
+func firstJSONByte(content []byte) byte {
+	for _, c := range content {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return c
+	}
+	return 0
+}
+
+// headerDoer sets a header on each request before passing it on
+type headerDoer struct {
+	next  Doer
+	name  string
+	value string
+}
+
+func (d *headerDoer) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set(d.name, d.value)
+	return d.next.Do(req)
+}
+
+// userAgentDoer sets the User-Agent header on each request before passing it on
+type userAgentDoer struct {
+	next      Doer
+	userAgent string
+}
+
+func (d *userAgentDoer) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", d.userAgent)
+	return d.next.Do(req)
+}
+
+// RedactURL returns rawurl with the value of the access_key parameter replaced, so it can be logged safely
+func RedactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+
+	query := u.Query()
+	if _, ok := query["access_key"]; !ok {
+		return rawurl
+	}
+
+	query.Set("access_key", "REDACTED")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// redactError redacts the API key from the url of a *url.Error, which the http package returns for a failed request
+func redactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	return &url.Error{Op: urlErr.Op, URL: RedactURL(urlErr.URL), Err: urlErr.Err}
+}
+
+// keyRedactedError is an error whose message has the API key redacted, wherever the key appears in it
+type keyRedactedError struct {
+	err error
+	key string
+}
+
+func (e *keyRedactedError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.key, "REDACTED")
+}
+
+func (e *keyRedactedError) Unwrap() error {
+	return e.err
+}
+
+// redactKey wraps err, if its message holds key, so the message has the key redacted
+// It catches the key in errors which redactError can't see, e.g. from a Doer which puts the url or headers in its own errors
+func redactKey(err error, key string) error {
+	if err == nil || key == "" || !strings.Contains(err.Error(), key) {
+		return err
 	}
 
-	return string(body), nil
+	return &keyRedactedError{err: err, key: key}
 }
@@ -1,9 +1,13 @@
 package restcountries
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,13 +27,12 @@ func TestGetUrlContentErrorEOF(t *testing.T) {
 		return &http.Response{}, errors.New("unexpected EOF")
 	}
 
-	gotContent, gotErr := getUrlContent("", mockedClient)
+	gotResp, gotErr := getUrlContent(context.Background(), "", nil, mockedClient)
 
-	wantContent := ""
 	wantErr := "unexpected EOF"
 
-	if gotContent != wantContent {
-		t.Errorf("got content %s; wanted %s", gotContent, wantContent)
+	if gotResp != nil {
+		t.Errorf("got response %v; wanted nil", gotResp)
 	}
 
 	if gotErr == nil || gotErr.Error() != wantErr {
@@ -51,16 +54,91 @@ func TestGetUrlContentErrorRead(t *testing.T) {
 	defer server.Close()
 
 	var myClient = &http.Client{Timeout: 10 * time.Second}
-	gotContent, gotErr := getUrlContent(server.URL, myClient)
+	gotResp, gotErr := getUrlContent(context.Background(), server.URL, nil, myClient)
 
-	wantContent := ""
 	wantErr := "unexpected EOF"
 
-	if gotContent != wantContent {
-		t.Errorf("got content %s; wanted %s", gotContent, wantContent)
+	if gotResp != nil {
+		t.Errorf("got response %v; wanted nil", gotResp)
 	}
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Errorf("got err %v; wanted %s", gotErr, wantErr)
 	}
 }
+
+func TestGetUrlContentContextCancelled(t *testing.T) {
+
+	// a server which blocks until the test finishes so only the context can end the request
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, gotErr := getUrlContent(ctx, server.URL, nil, &http.Client{})
+
+	if !errors.Is(gotErr, context.DeadlineExceeded) {
+		t.Errorf("got err %v; wanted %v", gotErr, context.DeadlineExceeded)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.countrylayer.com/v2/all?access_key=SECRET", "https://api.countrylayer.com/v2/all?access_key=REDACTED"},
+		{"https://api.countrylayer.com/v2/name/Estonia?access_key=SECRET&fullText=true", "https://api.countrylayer.com/v2/name/Estonia?access_key=REDACTED&fullText=true"},
+		{"https://restcountries.com/v3.1/all", "https://restcountries.com/v3.1/all"},
+	}
+
+	for _, test := range tests {
+		if got := RedactURL(test.url); got != test.want {
+			t.Errorf("got %q; want %q", got, test.want)
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	err := redactError(&url.Error{Op: "Get", URL: "https://api.countrylayer.com/v2/all?access_key=SECRET", Err: errors.New("connection refused")})
+
+	want := `Get "https://api.countrylayer.com/v2/all?access_key=REDACTED": connection refused`
+	if err.Error() != want {
+		t.Errorf("got %q; want %q", err.Error(), want)
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(urlErr.URL, "SECRET") {
+		t.Errorf("got %#v; want a *url.Error without the key", err)
+	}
+
+	other := errors.New("other")
+	if got := redactError(other); got != other {
+		t.Errorf("got %v; want the error unchanged", got)
+	}
+}
+
+func TestRedactKey(t *testing.T) {
+	err := redactKey(fmt.Errorf("request failed: %w", ErrRateLimited), "rate")
+	if err.Error() != "request failed: REDACTED limited" {
+		t.Errorf("got %q; want the key redacted", err.Error())
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("got %v; want it to wrap %v", err, ErrRateLimited)
+	}
+
+	other := errors.New("other")
+	for _, key := range []string{"", "SECRET"} {
+		if got := redactKey(other, key); got != other {
+			t.Errorf("got %v for key %q; want the error unchanged", got, key)
+		}
+	}
+	if redactKey(nil, "SECRET") != nil {
+		t.Errorf("got an error for nil")
+	}
+}
@@ -0,0 +1,138 @@
+package restcountries
+
+import (
+	"sort"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// LocalizedCountry is a country with its name in the language chosen by a Localizer
+type LocalizedCountry struct {
+	Country
+	DisplayName string // the name in the chosen language, or the English name when there is none
+}
+
+// Localizer lists countries with their names in the language which best matches a visitor's preferences, e.g. the
+// Accept-Language header of an HTTP request, sorted in the alphabetical order of that language
+// The languages it chooses from are English, those of the translations of the countries and those their native names
+// are written in, e.g. Estonian for Eesti. A language is chosen even when it names only some of the countries, and the
+// others then have their English names, e.g. every country but Estonia in Estonian
+// A Localizer is safe for concurrent use; the countries it returns share their slices and maps with those given to NewLocalizer
+type Localizer struct {
+	countries []Country
+	languages []language.Tag // English first, as the language to use when nothing matches
+	matcher   language.Matcher
+}
+
+// NewLocalizer creates a Localizer for countries, e.g. a result of All or of an Offline
+func NewLocalizer(countries []Country) *Localizer {
+	seen := map[string]bool{"en": true}
+	var keys []string
+	add := func(key string) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, country := range countries {
+		for key := range country.Translations {
+			add(key)
+		}
+		if lang, ok := nativeLanguage(country); ok {
+			add(lang.Iso6391)
+		}
+	}
+	sort.Strings(keys)
+
+	languages := []language.Tag{language.English}
+	for _, key := range keys {
+		if tag, err := language.Parse(key); err == nil {
+			languages = append(languages, tag)
+		}
+	}
+
+	return &Localizer{
+		countries: countries,
+		languages: languages,
+		matcher:   language.NewMatcher(languages),
+	}
+}
+
+// Languages returns the languages the Localizer chooses from, English first
+func (l *Localizer) Languages() []language.Tag {
+	return append([]language.Tag(nil), l.languages...)
+}
+
+// Match returns the language which best matches the given Accept-Language headers, or English if none matches
+// Invalid headers, or invalid parts of them, are ignored
+func (l *Localizer) Match(acceptLanguage ...string) language.Tag {
+	_, index := language.MatchStrings(l.matcher, acceptLanguage...)
+	return l.languages[index]
+}
+
+// MatchTags returns the language which best matches the given tags, in order of preference, or English if none matches
+func (l *Localizer) MatchTags(tags ...language.Tag) language.Tag {
+	_, index, confidence := l.matcher.Match(tags...)
+	if confidence == language.No {
+		return language.English
+	}
+	return l.languages[index]
+}
+
+// Localize returns the countries named and sorted in the language which best matches the given Accept-Language
+// headers, along with that language, e.g. to set the Content-Language of the response
+func (l *Localizer) Localize(acceptLanguage ...string) ([]LocalizedCountry, language.Tag) {
+	tag := l.Match(acceptLanguage...)
+	return l.Countries(tag), tag
+}
+
+// LocalizeTags is like Localize but takes the preferred languages as tags, in order of preference
+func (l *Localizer) LocalizeTags(tags ...language.Tag) ([]LocalizedCountry, language.Tag) {
+	tag := l.MatchTags(tags...)
+	return l.Countries(tag), tag
+}
+
+// Countries returns the countries named in the language of tag, sorted in the alphabetical order of that language
+// A country without a translation for tag is named with its native name if that is written in the language, e.g.
+// Eesti for Estonia in Estonian, and with its English name otherwise
+func (l *Localizer) Countries(tag language.Tag) []LocalizedCountry {
+	localized := make([]LocalizedCountry, len(l.countries))
+	for i, country := range l.countries {
+		localized[i] = LocalizedCountry{Country: country, DisplayName: displayName(country, tag)}
+	}
+
+	collator := collate.New(tag)
+	sort.SliceStable(localized, func(i, j int) bool {
+		return collator.CompareString(localized[i].DisplayName, localized[j].DisplayName) < 0
+	})
+
+	return localized
+}
+
+// displayName returns the name of country in the language of tag, see Localizer.Countries
+func displayName(country Country, tag language.Tag) string {
+	base, _ := tag.Base()
+	if base.String() == "en" {
+		return country.Name
+	}
+
+	if name, ok := country.Translations.Lookup(tag); ok {
+		return name
+	}
+
+	if lang, ok := nativeLanguage(country); ok && (lang.Is(base.String()) || lang.Is(base.ISO3())) {
+		return country.NativeName
+	}
+
+	return country.Name
+}
+
+// nativeLanguage returns the language the native name of country is written in, the first of its languages as in the
+// API, e.g. Finnish for Suomi rather than Swedish, or false if it has no native name or languages
+func nativeLanguage(country Country) (Language, bool) {
+	if country.NativeName == "" || len(country.Languages) == 0 {
+		return Language{}, false
+	}
+	return country.Languages[0], true
+}
@@ -0,0 +1,206 @@
+package restcountries
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+var localizeCountries = []Country{
+	{Name: "Japan", NativeName: "日本", Languages: []Language{{Iso6391: "ja", Iso6392: "jpn"}}, Translations: Translations{"de": "Japan", "fr": "Japon", "ja": "日本"}},
+	{Name: "Estonia", NativeName: "Eesti", Languages: []Language{{Iso6391: "et", Iso6392: "est"}}, Translations: Translations{"de": "Estland", "fr": "Estonie"}},
+	{Name: "Åland Islands", NativeName: "Åland", Languages: []Language{{Iso6391: "sv", Iso6392: "swe"}}, Translations: Translations{"de": "Åland", "fr": "Åland", "pt-BR": "Ilhas de Aland"}},
+	{Name: "Austria", NativeName: "Österreich", Languages: []Language{{Iso6391: "de", Iso6392: "deu"}}, Translations: Translations{"de": "Österreich", "fr": "Autriche"}},
+	{Name: "Zambia", NativeName: "Zambia", Translations: Translations{"de": "Sambia", "fr": "Zambie"}},
+}
+
+func displayNames(countries []LocalizedCountry) []string {
+	names := make([]string, len(countries))
+	for i, country := range countries {
+		names[i] = country.DisplayName
+	}
+	return names
+}
+
+func TestLocalizerLanguages(t *testing.T) {
+	got := NewLocalizer(localizeCountries).Languages()
+	want := []string{"en", "de", "et", "fr", "ja", "pt-BR", "sv"}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	for i, tag := range got {
+		if tag.String() != want[i] {
+			t.Errorf("got %v; want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestLocalizerMatch(t *testing.T) {
+	localizer := NewLocalizer(localizeCountries)
+
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"de-AT,de;q=0.9,en;q=0.8", "de"},
+		{"fr-CH, fr;q=0.9", "fr"},
+		{"fi,fr;q=0.5", "fr"},
+		{"sv-FI,fr;q=0.5", "sv"},
+		{"et-EE", "et"},
+		{"pt-BR", "pt-BR"},
+		{"ja-JP", "ja"},
+		{"en-GB", "en"},
+		{"fi", "en"},
+		{"", "en"},
+		{"not a language", "en"},
+	}
+
+	for _, test := range tests {
+		if got := localizer.Match(test.acceptLanguage).String(); got != test.want {
+			t.Errorf("%q: got %s; want %s", test.acceptLanguage, got, test.want)
+		}
+	}
+}
+
+func TestLocalizerMatchTags(t *testing.T) {
+	localizer := NewLocalizer(localizeCountries)
+
+	if got := localizer.MatchTags(language.Finnish, language.French); got != language.French {
+		t.Errorf("got %s; want fr", got)
+	}
+	if got := localizer.MatchTags(language.Finnish); got != language.English {
+		t.Errorf("got %s; want en", got)
+	}
+	if got := localizer.MatchTags(); got != language.English {
+		t.Errorf("got %s; want en", got)
+	}
+}
+
+func TestLocalizerLocalize(t *testing.T) {
+	localizer := NewLocalizer(localizeCountries)
+
+	tests := []struct {
+		acceptLanguage string
+		wantTag        string
+		want           []string
+	}{
+		// Å and Ö sort with A and O, rather than after Z as in byte order
+		{"en-US", "en", []string{"Åland Islands", "Austria", "Estonia", "Japan", "Zambia"}},
+		{"de-DE", "de", []string{"Åland", "Estland", "Japan", "Österreich", "Sambia"}},
+		{"fr", "fr", []string{"Åland", "Autriche", "Estonie", "Japon", "Zambie"}},
+		// Countries without a Japanese name fall back to English, and Japan has its translation
+		{"ja", "ja", []string{"Åland Islands", "Austria", "Estonia", "Zambia", "日本"}},
+		// Estonian is only spoken, not a translation, so Estonia has its native name and the rest their English names
+		{"et-EE, fi;q=0.8", "et", []string{"Åland Islands", "Austria", "Eesti", "Japan", "Zambia"}},
+		// Swedish is spoken in the Åland Islands, which have their native name, sorted after Z as Swedish sorts Å
+		{"sv", "sv", []string{"Austria", "Estonia", "Japan", "Zambia", "Åland"}},
+	}
+
+	for _, test := range tests {
+		countries, tag := localizer.Localize(test.acceptLanguage)
+		if tag.String() != test.wantTag {
+			t.Errorf("%q: got tag %s; want %s", test.acceptLanguage, tag, test.wantTag)
+		}
+
+		got := displayNames(countries)
+		if len(got) != len(test.want) {
+			t.Fatalf("%q: got %q; want %q", test.acceptLanguage, got, test.want)
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%q: got %q; want %q", test.acceptLanguage, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLocalizerLocalizeTags(t *testing.T) {
+	countries, tag := NewLocalizer(localizeCountries).LocalizeTags(language.MustParse("pt-BR"))
+	if tag.String() != "pt-BR" {
+		t.Errorf("got tag %s; want pt-BR", tag)
+	}
+
+	// Only the Åland Islands have a Brazilian Portuguese name
+	if countries[0].Name != "Austria" || countries[len(countries)-1].DisplayName != "Zambia" {
+		t.Errorf("got %q", displayNames(countries))
+	}
+	for _, country := range countries {
+		if country.Name == "Åland Islands" && country.DisplayName != "Ilhas de Aland" {
+			t.Errorf("got %q; want Ilhas de Aland", country.DisplayName)
+		}
+	}
+}
+
+func TestLocalizerNativeName(t *testing.T) {
+	countries := NewLocalizer(localizeCountries).Countries(language.Estonian)
+
+	for _, country := range countries {
+		want := country.Name
+		if country.Name == "Estonia" {
+			want = "Eesti"
+		}
+		if country.DisplayName != want {
+			t.Errorf("%s: got %q; want %q", country.Name, country.DisplayName, want)
+		}
+	}
+}
+
+func TestLocalizerNativeNameLanguage(t *testing.T) {
+	// the native name is written in the first language only, so Swedish speakers don't see Suomi
+	countries := []Country{
+		{Name: "Finland", NativeName: "Suomi", Languages: []Language{{Iso6391: "fi", Iso6392: "fin"}, {Iso6391: "sv", Iso6392: "swe"}}},
+		{Name: "Sweden", NativeName: "Sverige", Languages: []Language{{Iso6391: "sv", Iso6392: "swe"}}},
+		{Name: "Afghanistan", NativeName: "افغانستان", Languages: []Language{{Iso6392: "prs"}, {Iso6391: "tk", Iso6392: "tuk"}}},
+	}
+	localizer := NewLocalizer(countries)
+
+	if got, want := localizer.Languages(), []language.Tag{language.English, language.Finnish, language.Swedish}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	tests := []struct {
+		acceptLanguage string
+		wantTag        string
+		want           []string
+	}{
+		{"fi", "fi", []string{"Afghanistan", "Suomi", "Sweden"}},
+		{"sv", "sv", []string{"Afghanistan", "Finland", "Sverige"}},
+		{"tk", "en", []string{"Afghanistan", "Finland", "Sweden"}},
+	}
+
+	for _, test := range tests {
+		got, tag := localizer.Localize(test.acceptLanguage)
+		if tag.String() != test.wantTag {
+			t.Errorf("%q: got tag %s; want %s", test.acceptLanguage, tag, test.wantTag)
+		}
+		if names := displayNames(got); !reflect.DeepEqual(names, test.want) {
+			t.Errorf("%q: got %q; want %q", test.acceptLanguage, names, test.want)
+		}
+	}
+}
+
+func TestLocalizerOffline(t *testing.T) {
+	all, _ := NewOffline().All(AllOptions{})
+	localizer := NewLocalizer(all)
+
+	countries, tag := localizer.Localize("de-CH, de;q=0.9, en;q=0.8")
+	if tag.String() != "de" {
+		t.Fatalf("got tag %s; want de", tag)
+	}
+	if len(countries) != len(all) {
+		t.Fatalf("got %d countries; want %d", len(countries), len(all))
+	}
+
+	if countries[0].DisplayName != "Afghanistan" {
+		t.Errorf("got first %q; want Afghanistan", countries[0].DisplayName)
+	}
+	for _, country := range countries {
+		if country.Name == "Germany" && country.DisplayName != "Deutschland" {
+			t.Errorf("got %q; want Deutschland", country.DisplayName)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package restcountries
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Middleware wraps the Doer which sends requests, e.g. to add headers, log requests or record latency
+// It can change the request before passing it to next, and the response next returns
+type Middleware func(next Doer) Doer
+
+// DoerFunc adapts a function to the Doer interface, which is handy for writing a Middleware
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ResponseInfo describes a response for a ResponseHook
+type ResponseInfo struct {
+	URL        string        // the request url, with the API key redacted
+	StatusCode int           // the HTTP status, or 0 if the request failed
+	Header     http.Header   // the response headers, or nil if the request failed
+	Body       []byte        // the raw response body, before it is decoded, which must not be modified. It is nil for a response of LookupIter which is streamed
+	Duration   time.Duration // the time taken to send the request and read the response
+	Err        error         // the error if the request failed, e.g. a network error, with the API key redacted
+}
+
+// ResponseHook observes each response from the API, including retries, before the body is decoded
+// ctx is the context of the call. Responses served from the cache don't reach the hooks
+type ResponseHook func(ctx context.Context, info ResponseInfo)
+
+// LookupHook observes each lookup of the client, e.g. to trace it. It is called when the lookup starts, with the context
+// of the call and the request, and returns the context for the lookup, which reaches the response hooks, and a function
+// which is called when the lookup ends with the number of countries and the error
+// Lookups served from the cache, from a backend or by the fallbacks of WithFailover reach the hooks too
+type LookupHook func(ctx context.Context, req Request) (context.Context, func(results int, err error))
+
+// CacheHook observes each read of the cache set with WithCache, with the request url, with the API key redacted, and
+// whether the cache answered the lookup. ctx is the context of the lookup
+// Lookups with the CacheBypass or CacheRefresh mode don't read the cache, so they don't reach the hooks
+type CacheHook func(ctx context.Context, url string, hit bool)
+
+// WithMiddleware wraps the client's Doer in middleware, the first being the outermost
+// Every request of every endpoint passes through the middleware, including retries and cache revalidations
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(r *RestCountries) {
+		r.middleware = append(r.middleware, middleware...)
+	}
+}
+
+// WithOnResponse calls hooks, in order, with each response from the API
+func WithOnResponse(hooks ...ResponseHook) Option {
+	return func(r *RestCountries) {
+		r.hooks = append(r.hooks, hooks...)
+	}
+}
+
+// WithOnLookup calls hooks, in order, at the start of each lookup, including those of AllIter and LookupIter
+// The functions they return to end the lookup are called in reverse order
+func WithOnLookup(hooks ...LookupHook) Option {
+	return func(r *RestCountries) {
+		r.lookupHooks = append(r.lookupHooks, hooks...)
+	}
+}
+
+// WithOnCache calls hooks, in order, after each read of the cache
+func WithOnCache(hooks ...CacheHook) Option {
+	return func(r *RestCountries) {
+		r.cacheHooks = append(r.cacheHooks, hooks...)
+	}
+}
+
+// chain wraps client in middleware, the first being the outermost
+func chain(client Doer, middleware []Middleware) Doer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		client = middleware[i](client)
+	}
+	return client
+}
+
+// onResponse calls the response hooks for the request for url which started at start
+func (r *RestCountries) onResponse(ctx context.Context, url string, start time.Time, resp *response, err error) {
+	if len(r.hooks) == 0 {
+		return
+	}
+
+	info := ResponseInfo{
+		URL:      RedactURL(url),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	if resp != nil {
+		info.StatusCode = resp.status
+		info.Header = resp.header
+		info.Body = resp.content
+	}
+
+	for _, hook := range r.hooks {
+		hook(ctx, info)
+	}
+}
+
+// onCache calls the cache hooks for a read of the cache for url
+func (r *RestCountries) onCache(ctx context.Context, url string, hit bool) {
+	if len(r.cacheHooks) == 0 {
+		return
+	}
+
+	url = RedactURL(url)
+	for _, hook := range r.cacheHooks {
+		hook(ctx, url, hit)
+	}
+}
+
+// startLookup calls the lookup hooks for req, returning the context for the lookup and the function which ends it
+func (r *RestCountries) startLookup(ctx context.Context, req Request) (context.Context, func(results int, err error)) {
+	if len(r.lookupHooks) == 0 {
+		return ctx, func(int, error) {}
+	}
+
+	ends := make([]func(int, error), 0, len(r.lookupHooks))
+	for _, hook := range r.lookupHooks {
+		var end func(int, error)
+		ctx, end = hook(ctx, req)
+		if end != nil {
+			ends = append(ends, end)
+		}
+	}
+
+	return ctx, func(results int, err error) {
+		for i := len(ends) - 1; i >= 0; i-- {
+			ends[i](results, err)
+		}
+	}
+}
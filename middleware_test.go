@@ -0,0 +1,215 @@
+package restcountries
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var gotHeader, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Request-Id")
+		gotUserAgent = r.Header.Get("User-Agent")
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" "+req.Header.Get("User-Agent"))
+				return next.Do(req)
+			})
+		}
+	}
+	addHeader := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Request-Id", "42")
+			return next.Do(req)
+		})
+	}
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithUserAgent("test-agent"),
+		WithMiddleware(trace("outer"), trace("inner")), WithMiddleware(addHeader))
+
+	if _, err := testClient.All(AllOptions{}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if gotHeader != "42" {
+		t.Errorf("got X-Request-Id %q; want 42", gotHeader)
+	}
+	if gotUserAgent != "test-agent" {
+		t.Errorf("got User-Agent %q; want test-agent", gotUserAgent)
+	}
+	if want := []string{"outer test-agent", "inner test-agent"}; strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("got order %v; want %v", order, want)
+	}
+}
+
+func TestWithMiddlewareResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	// middleware can replace the response too
+	rewrite := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			resp.Body.Close()
+			resp.Body = ioutil.NopCloser(strings.NewReader(`[{"name":"Latvia"}]`))
+			return resp, nil
+		})
+	}
+
+	result, err := New("TEST_API_KEY", WithBaseURL(server.URL), WithMiddleware(rewrite)).All(AllOptions{})
+	if err != nil || len(result) != 1 || result[0].Name != "Latvia" {
+		t.Fatalf("got %v, %v; want Latvia", result, err)
+	}
+}
+
+func TestWithOnResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	type ctxKey struct{}
+	var infos []ResponseInfo
+	var gotValue interface{}
+	hook := func(ctx context.Context, info ResponseInfo) {
+		gotValue = ctx.Value(ctxKey{})
+		infos = append(infos, info)
+	}
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithOnResponse(hook))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	if _, err := testClient.NameContext(ctx, NameOptions{Name: "Estonia"}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(infos) != 1 {
+		t.Fatalf("got %d calls; want 1", len(infos))
+	}
+	info := infos[0]
+	if strings.Contains(info.URL, "TEST_API_KEY") || !strings.Contains(info.URL, "/name/Estonia") {
+		t.Errorf("got url %q; want the url with the key redacted", info.URL)
+	}
+	if info.StatusCode != http.StatusOK || info.Header.Get("X-Test") != "yes" || !strings.Contains(string(info.Body), "Estonia") {
+		t.Errorf("got %+v; want the response", info)
+	}
+	if info.Duration <= 0 || info.Err != nil {
+		t.Errorf("got duration %v and err %v", info.Duration, info.Err)
+	}
+	if gotValue != "value" {
+		t.Errorf("got context value %v; want the context of the call", gotValue)
+	}
+}
+
+func TestWithOnResponseError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var info ResponseInfo
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithOnResponse(func(ctx context.Context, i ResponseInfo) {
+		info = i
+	}))
+
+	testClient.All(AllOptions{})
+
+	if info.Err == nil || info.StatusCode != 0 || info.Body != nil {
+		t.Fatalf("got %+v; want the network error", info)
+	}
+}
+
+type lookupHookKey struct{}
+
+func TestWithOnLookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/name/Atlantis") {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintln(w, `{"status": 401, "message": "Unauthorized"}`)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"Estonia"}, {"name":"Latvia"}]`)
+	}))
+	defer server.Close()
+
+	var events []string
+	hook := func(name string) LookupHook {
+		return func(ctx context.Context, req Request) (context.Context, func(int, error)) {
+			events = append(events, fmt.Sprintf("start %s %s", name, req.Endpoint))
+			ctx = context.WithValue(ctx, lookupHookKey{}, name)
+			return ctx, func(results int, err error) {
+				events = append(events, fmt.Sprintf("end %s %d %v", name, results, err != nil))
+			}
+		}
+	}
+	onResponse := func(ctx context.Context, info ResponseInfo) {
+		events = append(events, fmt.Sprintf("response in %v", ctx.Value(lookupHookKey{})))
+	}
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithOnLookup(hook("a"), hook("b")), WithOnResponse(onResponse))
+
+	testClient.All(AllOptions{})
+	testClient.Name(NameOptions{Name: "Atlantis"})
+	for range testClient.AllIter(context.Background(), AllOptions{}) {
+	}
+	// an invalid request isn't a lookup
+	testClient.Name(NameOptions{})
+
+	want := []string{
+		"start a all", "start b all", "response in b", "end b 2 false", "end a 2 false",
+		"start a name", "start b name", "response in b", "end b 0 true", "end a 0 true",
+		"start a all", "start b all", "response in b", "end b 2 false", "end a 2 false",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("got %q; want %q", events, want)
+	}
+}
+
+func TestWithOnCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	var events []string
+	onCache := func(ctx context.Context, url string, hit bool) {
+		events = append(events, fmt.Sprintf("%s %v", strings.TrimPrefix(url, server.URL), hit))
+	}
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCache(NewMemoryCache(10), time.Minute), WithOnCache(onCache))
+
+	testClient.Name(NameOptions{Name: "Estonia"})
+	testClient.Name(NameOptions{Name: "Estonia"})
+	// lookups which don't read the cache don't reach the hooks
+	testClient.NameContext(WithCacheMode(context.Background(), CacheBypass), NameOptions{Name: "Estonia"})
+	testClient.NameContext(WithCacheMode(context.Background(), CacheRefresh), NameOptions{Name: "Estonia"})
+
+	want := []string{"/name/Estonia?access_key=REDACTED&fields= false", "/name/Estonia?access_key=REDACTED&fields= true"}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("got %q; want %q", events, want)
+	}
+
+	// without a cache there is nothing to read
+	events = nil
+	New("TEST_API_KEY", WithBaseURL(server.URL), WithOnCache(onCache)).Name(NameOptions{Name: "Estonia"})
+	if events != nil {
+		t.Fatalf("got %q; want no events", events)
+	}
+}
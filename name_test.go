@@ -39,7 +39,7 @@ func TestNameErrorUrl(t *testing.T) {
 		Name: "France",
 	})
 
-	wantErr := `Get "not%20a%20url/name/France?access_key=TEST_API_KEY&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/name/France?access_key=REDACTED&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
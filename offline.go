@@ -0,0 +1,279 @@
+package restcountries
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+//go:embed data/countries.json
+var offlineSnapshot []byte
+
+// Offline answers lookups from a snapshot of country data held in memory, without any network access
+// NewOffline uses the snapshot embedded in the package, which makes it usable where the API is unreachable, but has
+// less data than the API, see NewOffline
+// The methods match countries the same way as the API, see the methods of RestCountries
+// An Offline is safe for concurrent use, and every call returns countries which the caller is free to modify
+type Offline struct {
+	countries []Country
+	raw       []json.RawMessage // the JSON of each country, decoded again for every result so callers get their own copy
+}
+
+// NewOffline creates an Offline using the snapshot of all countries embedded in the package
+// See data/README.md for where the data comes from. The snapshot lacks some of the data of the API:
+//   - Population, Gini and Demonym are zero, so sorting by population, density or Gini keeps the original order and
+//     their ranges above zero match no country, e.g. Population(AtLeast(1))
+//   - the European Union is the only regional bloc, so RegionalBloc returns no countries for any other, e.g. EFTA
+//   - Flag is the emoji flag of the country, e.g. 🇪🇪, rather than the URL of an SVG image
+//   - no country has a Persian (fa) translation, and about a third lack each of the other languages of the API, e.g.
+//     91 of 247 have no German name, so LocalizedName and Localizer name them in English
+func NewOffline() *Offline {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(offlineSnapshot, &raw); err != nil {
+		panic("restcountries: invalid embedded snapshot: " + err.Error())
+	}
+
+	o := &Offline{raw: raw, countries: make([]Country, len(raw))}
+	for i, content := range raw {
+		if err := json.Unmarshal(content, &o.countries[i]); err != nil {
+			panic("restcountries: invalid embedded snapshot: " + err.Error())
+		}
+	}
+
+	return o
+}
+
+// NewOfflineFrom creates an Offline answering lookups from the given countries, e.g. a result of All saved earlier
+func NewOfflineFrom(countries []Country) *Offline {
+	o := &Offline{raw: make([]json.RawMessage, len(countries)), countries: make([]Country, len(countries))}
+	for i, country := range countries {
+		o.raw[i], _ = json.Marshal(country) // a Country always encodes
+		json.Unmarshal(o.raw[i], &o.countries[i])
+	}
+
+	return o
+}
+
+// All method returns all countries
+func (o *Offline) All(options AllOptions) ([]Country, error) {
+	return o.AllContext(context.Background(), options)
+}
+
+// AllContext is like All but returns the context error if ctx is done
+func (o *Offline) AllContext(ctx context.Context, options AllOptions) ([]Country, error) {
+	return o.find(ctx, options.Fields, func(Country) bool {
+		return true
+	})
+}
+
+// Name method searches countries by name, native name or alternative spelling, as the API does, using a partial match
+// unless NameOptions.FullText is true
+func (o *Offline) Name(options NameOptions) ([]Country, error) {
+	return o.NameContext(context.Background(), options)
+}
+
+// NameContext is like Name but returns the context error if ctx is done
+func (o *Offline) NameContext(ctx context.Context, options NameOptions) ([]Country, error) {
+	if options.Name == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	match := containsFold
+	if options.FullText {
+		match = strings.EqualFold
+	}
+
+	return o.find(ctx, options.Fields, func(c Country) bool {
+		if match(c.Name, options.Name) || (c.NativeName != "" && match(c.NativeName, options.Name)) {
+			return true
+		}
+		for _, spelling := range c.AltSpellings {
+			if match(spelling, options.Name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Capital method searches countries by capital city using a partial match
+func (o *Offline) Capital(options CapitalOptions) ([]Country, error) {
+	return o.CapitalContext(context.Background(), options)
+}
+
+// CapitalContext is like Capital but returns the context error if ctx is done
+func (o *Offline) CapitalContext(ctx context.Context, options CapitalOptions) ([]Country, error) {
+	if options.Capital == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, func(c Country) bool {
+		return c.Capital != "" && containsFold(c.Capital, options.Capital)
+	})
+}
+
+// Currency method searches countries by ISO 4217 currency code using an exact match
+func (o *Offline) Currency(options CurrencyOptions) ([]Country, error) {
+	return o.CurrencyContext(context.Background(), options)
+}
+
+// CurrencyContext is like Currency but returns the context error if ctx is done
+func (o *Offline) CurrencyContext(ctx context.Context, options CurrencyOptions) ([]Country, error) {
+	if options.Currency == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, HasCurrency(options.Currency))
+}
+
+// Language method searches countries by ISO 639-1 or ISO 639-2 language code using an exact match
+func (o *Offline) Language(options LanguageOptions) ([]Country, error) {
+	return o.LanguageContext(context.Background(), options)
+}
+
+// LanguageContext is like Language but returns the context error if ctx is done
+func (o *Offline) LanguageContext(ctx context.Context, options LanguageOptions) ([]Country, error) {
+	if options.Language == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, SpeaksLanguage(options.Language))
+}
+
+// Region method searches countries by region using an exact match
+func (o *Offline) Region(options RegionOptions) ([]Country, error) {
+	return o.RegionContext(context.Background(), options)
+}
+
+// RegionContext is like Region but returns the context error if ctx is done
+func (o *Offline) RegionContext(ctx context.Context, options RegionOptions) ([]Country, error) {
+	if options.Region == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, Region(options.Region))
+}
+
+// RegionalBloc method searches countries by regional bloc acronym using an exact match
+func (o *Offline) RegionalBloc(options RegionalBlocOptions) ([]Country, error) {
+	return o.RegionalBlocContext(context.Background(), options)
+}
+
+// RegionalBlocContext is like RegionalBloc but returns the context error if ctx is done
+func (o *Offline) RegionalBlocContext(ctx context.Context, options RegionalBlocOptions) ([]Country, error) {
+	if options.RegionalBloc == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, InBloc(options.RegionalBloc))
+}
+
+// CallingCode method searches countries by calling code using an exact match
+func (o *Offline) CallingCode(options CallingCodeOptions) ([]Country, error) {
+	return o.CallingCodeContext(context.Background(), options)
+}
+
+// CallingCodeContext is like CallingCode but returns the context error if ctx is done
+func (o *Offline) CallingCodeContext(ctx context.Context, options CallingCodeOptions) ([]Country, error) {
+	if options.CallingCode == "" {
+		return nil, ErrEmptySearchTerm
+	}
+
+	return o.find(ctx, options.Fields, func(c Country) bool {
+		for _, code := range c.CallingCodes {
+			if code == options.CallingCode {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Codes method searches countries by ISO 3166-1 2-letter or 3-letter country codes using an exact match
+// Like the API, no countries are returned if any of the codes doesn't match a country
+func (o *Offline) Codes(options CodesOptions) ([]Country, error) {
+	return o.CodesContext(context.Background(), options)
+}
+
+// CodesContext is like Codes but returns the context error if ctx is done
+func (o *Offline) CodesContext(ctx context.Context, options CodesOptions) ([]Country, error) {
+	if len(options.Codes) == 0 {
+		return nil, ErrEmptySearchTerm
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var countries []Country
+	for _, code := range options.Codes {
+		index := -1
+		for i, c := range o.countries {
+			if strings.EqualFold(c.Alpha2Code, code) || strings.EqualFold(c.Alpha3Code, code) {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			return nil, nil
+		}
+
+		countries = append(countries, o.country(index, options.Fields))
+	}
+
+	return countries, nil
+}
+
+// find returns a copy of each country which matches, keeping only the given fields
+func (o *Offline) find(ctx context.Context, fields []string, match func(Country) bool) ([]Country, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var countries []Country
+	for i, c := range o.countries {
+		if match(c) {
+			countries = append(countries, o.country(i, fields))
+		}
+	}
+
+	return countries, nil
+}
+
+// country returns a copy of the country at index, keeping only the given fields
+func (o *Offline) country(index int, fields []string) Country {
+	var c Country
+	json.Unmarshal(o.raw[index], &c) // the raw JSON was decoded successfully when the Offline was created
+
+	filterFields(&c, fields)
+	return c
+}
+
+// filterFields zeroes every field of the country which isn't in fields, the same as the Fields option of the API
+// Fields are matched by their JSON name, with the first character lowercased as in processFields
+func filterFields(c *Country, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[lCFirst(field)] = true
+	}
+
+	value := reflect.ValueOf(c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name := strings.Split(value.Type().Field(i).Tag.Get("json"), ",")[0]
+		if !keep[name] {
+			value.Field(i).Set(reflect.Zero(value.Field(i).Type()))
+		}
+	}
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,177 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOfflineSnapshot(t *testing.T) {
+	countries, err := NewOffline().All(AllOptions{})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(countries) < 240 {
+		t.Fatalf("got %d countries; want the full snapshot", len(countries))
+	}
+
+	for _, c := range countries {
+		if c.Name == "" || len(c.Alpha2Code) != 2 || len(c.Alpha3Code) != 3 {
+			t.Fatalf("got incomplete country %+v", c)
+		}
+	}
+}
+
+func TestOffline(t *testing.T) {
+	offline := NewOffline()
+
+	tests := []struct {
+		name      string
+		lookup    func() ([]Country, error)
+		wantNames []string
+		wantErr   error
+	}{
+		{
+			name:      "name partial",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "united states"}) },
+			wantNames: []string{"United States", "United States Minor Outlying Islands", "United States Virgin Islands"},
+		},
+		{
+			name:      "name full text",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "United States", FullText: true}) },
+			wantNames: []string{"United States"},
+		},
+		{
+			name:      "name full text alt spelling",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "French Republic", FullText: true}) },
+			wantNames: []string{"France"},
+		},
+		{
+			name:      "name native",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "Deutschland"}) },
+			wantNames: []string{"Germany"},
+		},
+		{
+			name:      "name native full text",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "eesti", FullText: true}) },
+			wantNames: []string{"Estonia"},
+		},
+		{
+			name:      "name not found",
+			lookup:    func() ([]Country, error) { return offline.Name(NameOptions{Name: "Atlantis"}) },
+			wantNames: nil,
+		},
+		{
+			name:    "name empty",
+			lookup:  func() ([]Country, error) { return offline.Name(NameOptions{}) },
+			wantErr: ErrEmptySearchTerm,
+		},
+		{
+			name:      "capital partial",
+			lookup:    func() ([]Country, error) { return offline.Capital(CapitalOptions{Capital: "lond"}) },
+			wantNames: []string{"United Kingdom"},
+		},
+		{
+			name:      "currency",
+			lookup:    func() ([]Country, error) { return offline.Currency(CurrencyOptions{Currency: "chf"}) },
+			wantNames: []string{"Liechtenstein", "Switzerland"},
+		},
+		{
+			name:      "language",
+			lookup:    func() ([]Country, error) { return offline.Language(LanguageOptions{Language: "TG"}) },
+			wantNames: []string{"Tajikistan"},
+		},
+		{
+			name:      "calling code",
+			lookup:    func() ([]Country, error) { return offline.CallingCode(CallingCodeOptions{CallingCode: "372"}) },
+			wantNames: []string{"Estonia"},
+		},
+		{
+			name:      "codes in the requested order",
+			lookup:    func() ([]Country, error) { return offline.Codes(CodesOptions{Codes: []string{"gb", "COL"}}) },
+			wantNames: []string{"United Kingdom", "Colombia"},
+		},
+		{
+			name:      "codes with one not found",
+			lookup:    func() ([]Country, error) { return offline.Codes(CodesOptions{Codes: []string{"GB", "XX"}}) },
+			wantNames: nil,
+		},
+		{
+			name:    "codes empty",
+			lookup:  func() ([]Country, error) { return offline.Codes(CodesOptions{}) },
+			wantErr: ErrEmptySearchTerm,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := test.lookup()
+
+		if test.wantErr != nil {
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("%s: want err %v, got: %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: want err nil, got: %v", test.name, err)
+		}
+
+		var gotNames []string
+		for _, c := range got {
+			gotNames = append(gotNames, c.Name)
+		}
+
+		if !reflect.DeepEqual(test.wantNames, gotNames) {
+			t.Fatalf("%s: want: %v, got: %v", test.name, test.wantNames, gotNames)
+		}
+	}
+}
+
+func TestOfflineRegions(t *testing.T) {
+	offline := NewOffline()
+
+	europe, _ := offline.Region(RegionOptions{Region: "europe"})
+	eu, _ := offline.RegionalBloc(RegionalBlocOptions{RegionalBloc: "EU"})
+
+	if len(eu) != 27 {
+		t.Fatalf("got %d EU countries; want 27", len(eu))
+	}
+
+	if len(europe) <= len(eu) {
+		t.Fatalf("got %d countries in Europe; want more than the %d in the EU", len(europe), len(eu))
+	}
+}
+
+func TestOfflineFields(t *testing.T) {
+	got, _ := NewOffline().Codes(CodesOptions{Codes: []string{"FR"}, Fields: []string{"Name", "Capital"}})
+
+	want := []Country{{Name: "France", Capital: "Paris"}}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+}
+
+func TestOfflineCopies(t *testing.T) {
+	offline := NewOfflineFrom([]Country{{Name: "Testland", Alpha2Code: "TL", CallingCodes: []string{"999"}}})
+
+	first, _ := offline.All(AllOptions{})
+	first[0].CallingCodes[0] = "0"
+
+	second, _ := offline.CallingCode(CallingCodeOptions{CallingCode: "999"})
+	if len(second) != 1 || second[0].CallingCodes[0] != "999" {
+		t.Fatalf("got %v; want the original Testland", second)
+	}
+}
+
+func TestOfflineContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewOffline().AllContext(ctx, AllOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v; want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,65 @@
+package restcountries
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option configures a client created by New
+type Option func(*RestCountries)
+
+// WithBaseURL overrides the API root url, e.g. http://api.countrylayer.com/v2 for the free plan which doesn't support https
+// The default is the root of the provider, see WithProvider
+func WithBaseURL(url string) Option {
+	return func(r *RestCountries) {
+		r.apiRoot = url
+	}
+}
+
+// WithTimeout sets the timeout for each request, including reading the response. The default of 0 means no timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *RestCountries) {
+		r.timeout = timeout
+	}
+}
+
+// WithHTTPClient sends every request through client instead of http.DefaultClient
+// Any Doer can be used, e.g. an *http.Client with a proxy, mTLS or instrumented transport
+func WithHTTPClient(client Doer) Option {
+	return func(r *RestCountries) {
+		if client != nil {
+			r.client = client
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(r *RestCountries) {
+		r.userAgent = userAgent
+	}
+}
+
+// WithNotFoundError makes a search which matches no countries return an error matching ErrNotFound, instead of an empty slice
+func WithNotFoundError() Option {
+	return func(r *RestCountries) {
+		r.notFoundError = true
+	}
+}
+
+// WithLogger logs each request and response, and each lookup answered by the cache, to logger at debug level
+// Urls are logged with the API key redacted
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *RestCountries) {
+		r.logger = logger
+	}
+}
+
+// WithAPIKeyHeader sends the API key in the request header name, e.g. apikey, instead of in the url, where the API or a gateway
+// in front of it accepts that. The key then never appears in urls, so it stays out of logs, cache keys and errors
+// The header is set after any middleware has run, so middleware which logs requests doesn't see the key either
+func WithAPIKeyHeader(name string) Option {
+	return func(r *RestCountries) {
+		r.apiKeyHeader = name
+	}
+}
@@ -0,0 +1,209 @@
+package restcountries
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY",
+		WithBaseURL(server.URL),
+		WithTimeout(10*time.Second),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("test-agent/1.0"),
+	)
+
+	result, err := testClient.Name(NameOptions{Name: "France"})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "France" {
+		t.Fatalf("got %v; want France", result)
+	}
+
+	if gotUserAgent != "test-agent/1.0" {
+		t.Fatalf("got user agent %s; want test-agent/1.0", gotUserAgent)
+	}
+}
+
+func TestNewWithTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithTimeout(50*time.Millisecond))
+
+	_, gotErr := testClient.All(AllOptions{})
+
+	if !errors.Is(gotErr, context.DeadlineExceeded) {
+		t.Fatalf("got %v; want %v", gotErr, context.DeadlineExceeded)
+	}
+}
+
+func TestNewWithNilHTTPClient(t *testing.T) {
+	testClient := New("TEST_API_KEY", WithHTTPClient(nil))
+
+	if testClient.client != http.DefaultClient {
+		t.Fatalf("got client %v; want http.DefaultClient", testClient.client)
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	testClient := New("SECRET_KEY", WithBaseURL(server.URL), WithLogger(logger), WithCache(NewMemoryCache(10), time.Minute))
+	for i := 0; i < 2; i++ {
+		if _, err := testClient.Name(NameOptions{Name: "Estonia"}); err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+	}
+
+	logs := buf.String()
+	for _, want := range []string{"restcountries request", "restcountries response", "status=200", "restcountries cache hit", "access_key=REDACTED"} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("got logs %q; want %q in them", logs, want)
+		}
+	}
+	if strings.Contains(logs, "SECRET_KEY") {
+		t.Errorf("got logs %q; want the key redacted", logs)
+	}
+
+	// nothing is logged above debug level
+	buf.Reset()
+	logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	New("SECRET_KEY", WithBaseURL(server.URL), WithLogger(logger)).All(AllOptions{})
+	if buf.Len() != 0 {
+		t.Errorf("got logs %q at info level; want none", buf.String())
+	}
+}
+
+func TestWithLoggerRequestFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := New("SECRET_KEY", WithBaseURL(server.URL), WithLogger(logger)).All(AllOptions{})
+	if err == nil || strings.Contains(err.Error(), "SECRET_KEY") {
+		t.Fatalf("got err %v; want an error without the key", err)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "restcountries request failed") || strings.Contains(logs, "SECRET_KEY") {
+		t.Errorf("got logs %q; want the failure without the key", logs)
+	}
+}
+
+func TestWithLoggerDoerError(t *testing.T) {
+	// a Doer which puts the url and the key header in its own error
+	leaky := DoerFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, fmt.Errorf("GET %s with key %q failed", req.URL, req.Header.Get("apikey"))
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var hookErr error
+	onResponse := func(ctx context.Context, info ResponseInfo) {
+		hookErr = info.Err
+	}
+
+	for _, opts := range [][]Option{{}, {WithAPIKeyHeader("apikey")}} {
+		buf.Reset()
+		hookErr = nil
+		opts = append(opts, WithHTTPClient(leaky), WithLogger(logger), WithOnResponse(onResponse))
+
+		_, err := New("SECRET_KEY", opts...).All(AllOptions{})
+		if err == nil || strings.Contains(err.Error(), "SECRET_KEY") {
+			t.Fatalf("got err %v; want an error without the key", err)
+		}
+		if hookErr == nil || strings.Contains(hookErr.Error(), "SECRET_KEY") {
+			t.Errorf("got hook err %v; want an error without the key", hookErr)
+		}
+
+		logs := buf.String()
+		if !strings.Contains(logs, "restcountries request failed") || strings.Contains(logs, "SECRET_KEY") {
+			t.Errorf("got logs %q; want the failure without the key", logs)
+		}
+	}
+}
+
+func TestWithAPIKeyHeader(t *testing.T) {
+	var gotURL, gotKey, gotMiddlewareKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		gotKey = r.Header.Get("apikey")
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	spy := func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			gotMiddlewareKey = req.Header.Get("apikey")
+			return next.Do(req)
+		})
+	}
+
+	testClient := New("SECRET_KEY", WithBaseURL(server.URL), WithAPIKeyHeader("apikey"), WithMiddleware(spy))
+	if _, err := testClient.Name(NameOptions{Name: "Estonia"}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if gotKey != "SECRET_KEY" {
+		t.Errorf("got header %q; want SECRET_KEY", gotKey)
+	}
+	if strings.Contains(gotURL, "access_key") {
+		t.Errorf("got url %q; want no access_key", gotURL)
+	}
+	if gotMiddlewareKey != "" {
+		t.Errorf("got header %q in the middleware; want it set after the middleware", gotMiddlewareKey)
+	}
+}
+
+func TestAPIKeyNotInErrors(t *testing.T) {
+	// a Doer which puts the url in its own error, which isn't a *url.Error
+	leaky := &ClientMock{DoFunc: func(req *http.Request) (*http.Response, error) {
+		return nil, fmt.Errorf("proxy refused %s with key %s", req.URL, req.Header.Get("apikey"))
+	}}
+
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"query", []Option{WithHTTPClient(leaky)}},
+		{"header", []Option{WithHTTPClient(leaky), WithAPIKeyHeader("apikey")}},
+		{"failover", []Option{WithHTTPClient(leaky), WithFailover(time.Minute, New("SECRET_KEY", WithHTTPClient(leaky)))}},
+	}
+
+	for _, test := range tests {
+		_, err := New("SECRET_KEY", test.options...).All(AllOptions{})
+		if err == nil || strings.Contains(err.Error(), "SECRET_KEY") || !strings.Contains(err.Error(), "REDACTED") {
+			t.Errorf("%s: got err %v; want the key redacted", test.name, err)
+		}
+	}
+}
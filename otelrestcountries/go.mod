@@ -0,0 +1,25 @@
+module github.com/chriscross0/go-restcountries/v2/otelrestcountries
+
+go 1.23.0
+
+require (
+	github.com/chriscross0/go-restcountries/v2 v2.1.0
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/metric v1.38.0
+	go.opentelemetry.io/otel/sdk v1.38.0
+	go.opentelemetry.io/otel/sdk/metric v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+)
+
+// The replace builds against the parent module in this repository. It is ignored by users of the module, who get the
+// required release: tag the parent module v2.1.0 before tagging this module
+replace github.com/chriscross0/go-restcountries/v2 => ../
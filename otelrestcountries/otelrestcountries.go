@@ -0,0 +1,232 @@
+// Package otelrestcountries instruments go-restcountries with OpenTelemetry
+//
+// It records a span for each lookup, e.g. restcountries.Name, with the endpoint, the search term, the number of results,
+// whether the cache answered it, when the lookup read one, and the API status, and counts lookups, errors and their latency.
+// It is a separate module, so only users who want the instrumentation depend on OpenTelemetry
+package otelrestcountries
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chriscross0/go-restcountries/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and the meter
+const ScopeName = "github.com/chriscross0/go-restcountries/v2/otelrestcountries"
+
+// Attribute keys of the lookup spans and metrics
+const (
+	EndpointKey   = attribute.Key("restcountries.endpoint")
+	TermKey       = attribute.Key("restcountries.term")
+	ResultsKey    = attribute.Key("restcountries.results")
+	CacheHitKey   = attribute.Key("restcountries.cache.hit")
+	RequestsKey   = attribute.Key("restcountries.http.requests")
+	StatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// spanNames are the span names of the endpoints, after the methods of RestCountries
+var spanNames = map[restcountries.Endpoint]string{
+	restcountries.EndpointAll:          "restcountries.All",
+	restcountries.EndpointName:         "restcountries.Name",
+	restcountries.EndpointCapital:      "restcountries.Capital",
+	restcountries.EndpointCurrency:     "restcountries.Currency",
+	restcountries.EndpointLanguage:     "restcountries.Language",
+	restcountries.EndpointRegion:       "restcountries.Region",
+	restcountries.EndpointRegionalBloc: "restcountries.RegionalBloc",
+	restcountries.EndpointCallingCode:  "restcountries.CallingCode",
+	restcountries.EndpointCodes:        "restcountries.Codes",
+	restcountries.EndpointQuery:        "restcountries.Query",
+}
+
+// Instrumentation records spans and metrics for lookups
+type Instrumentation struct {
+	tracer   trace.Tracer
+	lookups  metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures an Instrumentation
+type Option func(*config)
+
+// WithTracerProvider records spans with provider instead of the global tracer provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records metrics with provider instead of the global meter provider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// New creates an Instrumentation, which uses the global tracer and meter providers unless options are given
+func New(opts ...Option) (*Instrumentation, error) {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	meter := c.meterProvider.Meter(ScopeName)
+	i := &Instrumentation{tracer: c.tracerProvider.Tracer(ScopeName)}
+
+	var err error
+	if i.lookups, err = meter.Int64Counter("restcountries.lookups",
+		metric.WithDescription("The number of lookups"), metric.WithUnit("{lookup}")); err != nil {
+		return nil, err
+	}
+	if i.errors, err = meter.Int64Counter("restcountries.lookup.errors",
+		metric.WithDescription("The number of lookups which failed"), metric.WithUnit("{lookup}")); err != nil {
+		return nil, err
+	}
+	if i.duration, err = meter.Float64Histogram("restcountries.lookup.duration",
+		metric.WithDescription("The duration of lookups"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// NewClient creates a client, like restcountries.New, whose every lookup is instrumented, including those of AllIter and LookupIter
+// Its spans also record the responses of the API: the status, the number of requests and, when the lookup read the cache,
+// whether the cache answered it
+// It is the client itself, so its accessors, e.g. CircuitState, and streaming work as without the instrumentation
+func (i *Instrumentation) NewClient(apiKey string, opts ...restcountries.Option) *restcountries.RestCountries {
+	return restcountries.New(apiKey, append(opts[:len(opts):len(opts)], i.ClientOption())...)
+}
+
+// ClientOption instruments a client created with restcountries.New, the same as NewClient
+func (i *Instrumentation) ClientOption() restcountries.Option {
+	return func(r *restcountries.RestCountries) {
+		restcountries.WithOnResponse(responseHook)(r)
+		restcountries.WithOnCache(cacheHook)(r)
+		restcountries.WithOnLookup(func(ctx context.Context, req restcountries.Request) (context.Context, func(int, error)) {
+			return i.start(ctx, req, true)
+		})(r)
+	}
+}
+
+// Wrap returns a Backend which instruments each lookup before passing it to backend, e.g. an Offline or a client with a fallback
+// Unlike with NewClient, the spans don't record the responses of the API
+func (i *Instrumentation) Wrap(backend restcountries.Backend) restcountries.Backend {
+	return &instrumented{backend: backend, instrumentation: i}
+}
+
+type instrumented struct {
+	backend         restcountries.Backend
+	instrumentation *Instrumentation
+}
+
+func (b *instrumented) Lookup(ctx context.Context, req restcountries.Request) ([]restcountries.Country, error) {
+	ctx, end := b.instrumentation.start(ctx, req, false)
+	countries, err := b.backend.Lookup(ctx, req)
+	end(len(countries), err)
+	return countries, err
+}
+
+// cacheHook records whether the cache answered the lookup
+func cacheHook(ctx context.Context, url string, hit bool) {
+	if l, ok := ctx.Value(lookupKey{}).(*lookup); ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.cacheHit = &hit
+	}
+}
+
+// responseHook records a response of the API in the lookup which made it
+func responseHook(ctx context.Context, info restcountries.ResponseInfo) {
+	if l, ok := ctx.Value(lookupKey{}).(*lookup); ok {
+		l.response(info)
+	}
+}
+
+// lookupKey is the context key of the lookup in flight
+type lookupKey struct{}
+
+// lookup collects the responses of a lookup from responseHook, and whether the cache answered it from cacheHook
+type lookup struct {
+	mu       sync.Mutex
+	requests int
+	status   int
+	cacheHit *bool // nil when the lookup didn't read the cache
+}
+
+func (l *lookup) response(info restcountries.ResponseInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests++
+	l.status = info.StatusCode
+}
+
+// start starts the span of a lookup, returning the context for the lookup and the function which ends it with the
+// number of countries and the error. With responses, the responses of the API reach responseHook and the span records them
+func (i *Instrumentation) start(ctx context.Context, req restcountries.Request, responses bool) (context.Context, func(int, error)) {
+	name, ok := spanNames[req.Endpoint]
+	if !ok {
+		name = "restcountries." + string(req.Endpoint)
+	}
+
+	attrs := []attribute.KeyValue{EndpointKey.String(string(req.Endpoint))}
+	term := req.Term
+	switch req.Endpoint {
+	case restcountries.EndpointCodes:
+		term = strings.Join(req.Codes, ",")
+	case restcountries.EndpointQuery:
+		term = req.Path
+	}
+
+	ctx, span := i.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, TermKey.String(term))...))
+
+	l := &lookup{}
+	ctx = context.WithValue(ctx, lookupKey{}, l)
+	start := time.Now()
+
+	return ctx, func(results int, err error) {
+		defer span.End()
+		elapsed := time.Since(start)
+
+		span.SetAttributes(ResultsKey.Int(results))
+		if responses {
+			// a lookup without requests may also have been answered by an identical lookup in flight, a backend or a
+			// fallback, so only a lookup which read the cache records whether it was a hit
+			l.mu.Lock()
+			span.SetAttributes(RequestsKey.Int(l.requests))
+			if l.cacheHit != nil {
+				span.SetAttributes(CacheHitKey.Bool(*l.cacheHit))
+			}
+			if l.status != 0 {
+				span.SetAttributes(StatusCodeKey.Int(l.status))
+			}
+			l.mu.Unlock()
+		}
+
+		measurement := metric.WithAttributes(attrs...)
+		i.lookups.Add(ctx, 1, measurement)
+		i.duration.Record(ctx, elapsed.Seconds(), measurement)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			i.errors.Add(ctx, 1, measurement)
+		}
+	}
+}
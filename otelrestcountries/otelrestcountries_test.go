@@ -0,0 +1,258 @@
+package otelrestcountries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chriscross0/go-restcountries/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestInstrumentation(t *testing.T) (*Instrumentation, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	i, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return i, spans, reader
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	i, spans, _ := newTestInstrumentation(t)
+	client := i.NewClient("TEST_API_KEY", restcountries.WithBaseURL(server.URL),
+		restcountries.WithCache(restcountries.NewMemoryCache(10), time.Minute))
+
+	for n := 0; n < 2; n++ {
+		if _, err := client.Name(restcountries.NameOptions{Name: "Estonia"}); err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("got %d spans; want 2", len(ended))
+	}
+
+	tests := []struct {
+		cacheHit bool
+		requests int64
+	}{
+		{false, 1},
+		{true, 0},
+	}
+
+	for n, test := range tests {
+		span := ended[n]
+		if span.Name() != "restcountries.Name" {
+			t.Errorf("got span %q; want restcountries.Name", span.Name())
+		}
+
+		attrs := attributes(span)
+		if got := attrs[EndpointKey].AsString(); got != "name" {
+			t.Errorf("got endpoint %q; want name", got)
+		}
+		if got := attrs[TermKey].AsString(); got != "Estonia" {
+			t.Errorf("got term %q; want Estonia", got)
+		}
+		if got := attrs[ResultsKey].AsInt64(); got != 1 {
+			t.Errorf("got %d results; want 1", got)
+		}
+		if got := attrs[CacheHitKey].AsBool(); got != test.cacheHit {
+			t.Errorf("span %d: got cache hit %v; want %v", n, got, test.cacheHit)
+		}
+		if got := attrs[RequestsKey].AsInt64(); got != test.requests {
+			t.Errorf("span %d: got %d requests; want %d", n, got, test.requests)
+		}
+	}
+
+	if got := attributes(ended[0])[StatusCodeKey].AsInt64(); got != http.StatusOK {
+		t.Errorf("got status %d; want 200", got)
+	}
+}
+
+func TestNewClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintln(w, `{"status": 401, "message": "Unauthorized"}`)
+	}))
+	defer server.Close()
+
+	i, spans, reader := newTestInstrumentation(t)
+	client := i.NewClient("TEST_API_KEY", restcountries.WithBaseURL(server.URL))
+
+	_, err := client.Codes(restcountries.CodesOptions{Codes: []string{"EE", "LV"}})
+	if !errors.Is(err, restcountries.ErrInvalidAPIKey) {
+		t.Fatalf("got err %v; want %v", err, restcountries.ErrInvalidAPIKey)
+	}
+
+	span := spans.Ended()[0]
+	if span.Name() != "restcountries.Codes" || span.Status().Code != codes.Error {
+		t.Errorf("got span %q with status %v; want restcountries.Codes with an error", span.Name(), span.Status())
+	}
+	attrs := attributes(span)
+	if got := attrs[TermKey].AsString(); got != "EE,LV" {
+		t.Errorf("got term %q; want EE,LV", got)
+	}
+	if got := attrs[StatusCodeKey].AsInt64(); got != http.StatusUnauthorized {
+		t.Errorf("got status %d; want 401", got)
+	}
+	if _, ok := attrs[CacheHitKey]; ok {
+		t.Errorf("got a cache hit attribute without a cache")
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]bool{}
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			got[m.Name] = true
+		}
+	}
+	for _, name := range []string{"restcountries.lookups", "restcountries.lookup.errors", "restcountries.lookup.duration"} {
+		if !got[name] {
+			t.Errorf("got no %s metric", name)
+		}
+	}
+}
+
+func TestNewClientIsTheClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"status": 503, "message": "Service Unavailable"}`)
+	}))
+	defer server.Close()
+
+	i, spans, _ := newTestInstrumentation(t)
+
+	// the option isn't appended to the caller's slice
+	opts := make([]restcountries.Option, 2, 3)
+	opts[0] = restcountries.WithBaseURL(server.URL)
+	opts[1] = restcountries.WithCircuitBreaker(1, time.Minute)
+	spare := restcountries.WithTimeout(time.Second)
+	extended := append(opts, spare)
+
+	client := i.NewClient("TEST_API_KEY", opts...)
+	if fmt.Sprint(extended[2]) != fmt.Sprint(spare) {
+		t.Fatalf("got the caller's options overwritten")
+	}
+
+	client.All(restcountries.AllOptions{})
+	if got := client.CircuitState(); got != restcountries.CircuitOpen {
+		t.Fatalf("got state %v; want %v", got, restcountries.CircuitOpen)
+	}
+	if got := len(spans.Ended()); got != 1 {
+		t.Fatalf("got %d spans; want 1", got)
+	}
+}
+
+func TestNewClientIter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"Estonia"}, {"name":"Latvia"}, {"name":"Lithuania"}]`)
+	}))
+	defer server.Close()
+
+	i, spans, _ := newTestInstrumentation(t)
+	client := i.NewClient("TEST_API_KEY", restcountries.WithBaseURL(server.URL))
+
+	count := 0
+	for _, err := range client.AllIter(context.Background(), restcountries.AllOptions{}) {
+		if err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+		count++
+	}
+
+	ended := spans.Ended()
+	if count != 3 || len(ended) != 1 {
+		t.Fatalf("got %d countries and %d spans; want 3 and 1", count, len(ended))
+	}
+
+	attrs := attributes(ended[0])
+	if ended[0].Name() != "restcountries.All" || attrs[ResultsKey].AsInt64() != 3 || attrs[StatusCodeKey].AsInt64() != http.StatusOK {
+		t.Errorf("got span %q with %d results and status %d; want restcountries.All with 3 and 200",
+			ended[0].Name(), attrs[ResultsKey].AsInt64(), attrs[StatusCodeKey].AsInt64())
+	}
+}
+
+func TestNewClientBackend(t *testing.T) {
+	i, spans, _ := newTestInstrumentation(t)
+	client := i.NewClient("", restcountries.WithBackend(restcountries.NewOffline()))
+
+	if _, err := client.Name(restcountries.NameOptions{Name: "Estonia"}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	// the backend answered without any request, but not from a cache
+	attrs := attributes(spans.Ended()[0])
+	if _, ok := attrs[CacheHitKey]; ok {
+		t.Errorf("got cache hit %v; want no cache hit attribute", attrs[CacheHitKey].AsBool())
+	}
+	if got := attrs[RequestsKey].AsInt64(); got != 0 {
+		t.Errorf("got %d requests; want 0", got)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	i, spans, reader := newTestInstrumentation(t)
+	client := restcountries.New("", restcountries.WithBackend(i.Wrap(restcountries.NewOffline())))
+
+	result, err := client.Region(restcountries.RegionOptions{Region: "Europe"})
+	if err != nil || len(result) == 0 {
+		t.Fatalf("got %v, %v; want the European countries", result, err)
+	}
+
+	span := spans.Ended()[0]
+	attrs := attributes(span)
+	if span.Name() != "restcountries.Region" || attrs[ResultsKey].AsInt64() != int64(len(result)) {
+		t.Errorf("got span %q with %d results; want restcountries.Region with %d", span.Name(), attrs[ResultsKey].AsInt64(), len(result))
+	}
+	if _, ok := attrs[CacheHitKey]; ok {
+		t.Errorf("got a cache hit attribute for a wrapped backend")
+	}
+
+	var metrics metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &metrics); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range metrics.ScopeMetrics[0].Metrics {
+		if m.Name != "restcountries.lookups" {
+			continue
+		}
+		sum := m.Data.(metricdata.Sum[int64])
+		if len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 1 {
+			t.Errorf("got lookups %+v; want 1", sum.DataPoints)
+		}
+	}
+}
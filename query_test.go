@@ -0,0 +1,139 @@
+package restcountries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestQuerySimple(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		fmt.Fprintln(w, `[{"name":"Estonia", "subregion": "Northern Europe"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	result, err := testClient.Query(QueryOptions{
+		Path:   "/subregion/Northern Europe",
+		Params: url.Values{"status": {"true"}},
+		Fields: []string{"Name", "Subregion"},
+	})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "Estonia" {
+		t.Fatalf("got %v; want Estonia", result)
+	}
+
+	wantURL := "/subregion/Northern%20Europe?access_key=TEST_API_KEY&fields=name%3Bsubregion%3B&status=true"
+	if gotURL != wantURL {
+		t.Fatalf("got url %s; want %s", gotURL, wantURL)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		input      QueryOptions
+		provider   Provider
+		response   string
+		httpStatus int
+		options    []Option
+		wantURL    string
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			// empty path
+			input:   QueryOptions{},
+			wantErr: ErrEmptySearchTerm,
+		},
+		{
+			// path without a leading slash
+			input:     QueryOptions{Path: "independent"},
+			response:  `[{"name":"Estonia"}, {"name":"Latvia"}]`,
+			wantURL:   "/independent?access_key=TEST_API_KEY&fields=",
+			wantCount: 2,
+		},
+		{
+			// not found, like every other endpoint
+			input:      QueryOptions{Path: "/subregion/Atlantis"},
+			response:   `{"status": 404, "message": "Not Found"}`,
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			// not found error
+			input:      QueryOptions{Path: "/subregion/Atlantis"},
+			response:   `{"status": 404, "message": "Not Found"}`,
+			httpStatus: http.StatusNotFound,
+			options:    []Option{WithNotFoundError()},
+			wantErr:    ErrNotFound,
+		},
+		{
+			// another provider
+			input:     QueryOptions{Path: "/independent", Params: url.Values{"status": {"true"}}, Fields: []string{"Name"}},
+			provider:  RestCountriesV3,
+			response:  `[{"name": {"common": "Estonia"}}]`,
+			wantURL:   "/independent?fields=name&status=true",
+			wantCount: 1,
+		},
+	}
+
+	for _, test := range tests {
+		var gotURL string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotURL = r.URL.String()
+			if test.httpStatus != 0 {
+				w.WriteHeader(test.httpStatus)
+			}
+			fmt.Fprintln(w, test.response)
+		}))
+
+		options := append(test.options, WithBaseURL(server.URL))
+		if test.provider != nil {
+			options = append(options, WithProvider(test.provider))
+		}
+
+		result, err := New("TEST_API_KEY", options...).Query(test.input)
+		server.Close()
+
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: got err %v; want %v", test.input.Path, err, test.wantErr)
+			continue
+		}
+		if len(result) != test.wantCount {
+			t.Errorf("%s: got %d countries; want %d", test.input.Path, len(result), test.wantCount)
+		}
+		if test.wantURL != "" && gotURL != test.wantURL {
+			t.Errorf("%s: got url %s; want %s", test.input.Path, gotURL, test.wantURL)
+		}
+	}
+}
+
+func TestQueryBadRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"status": 400, "message": "Bad Request"}`)
+	}))
+	defer server.Close()
+
+	_, err := New("TEST_API_KEY", WithBaseURL(server.URL)).Query(QueryOptions{Path: "/independent", Params: url.Values{"status": {"maybe"}}})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || errors.Is(err, ErrNotFound) {
+		t.Fatalf("got err %v; want a 400 *APIError", err)
+	}
+}
+
+func TestQueryOffline(t *testing.T) {
+	_, err := New("", WithBackend(NewOffline())).Query(QueryOptions{Path: "/independent"})
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("got err %v; want %v", err, ErrUnsupported)
+	}
+}
@@ -0,0 +1,100 @@
+package restcountries
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitStats reports how the client-side rate limiter has delayed requests
+type RateLimitStats struct {
+	Requests  int64         // the number of requests which passed through the limiter
+	Delayed   int64         // the number of requests which had to wait for a token
+	TotalWait time.Duration // the total time requests spent waiting
+	MaxWait   time.Duration // the longest time a single request waited
+}
+
+// rateLimiter is a token bucket which holds up to burst tokens and refills at perSecond tokens per second
+// Each request takes a token, waiting for one to become available if the bucket is empty
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+	stats     RateLimitStats
+}
+
+// WithRateLimit limits the client to perSecond requests per second, allowing bursts of up to burst requests
+// Requests, including retries, wait for the limiter before they are sent, giving up if their context is cancelled
+// A client shared between goroutines shares the limit, which helps to stay within the quota of a countrylayer plan
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(r *RestCountries) {
+		if perSecond <= 0 {
+			r.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		r.limiter = &rateLimiter{
+			perSecond: perSecond,
+			burst:     float64(burst),
+			tokens:    float64(burst),
+			last:      time.Now(),
+		}
+	}
+}
+
+// RateLimitStats returns the statistics of the rate limiter set with WithRateLimit, or zero values if there is none
+func (r *RestCountries) RateLimitStats() RateLimitStats {
+	if r.limiter == nil {
+		return RateLimitStats{}
+	}
+
+	r.limiter.mu.Lock()
+	defer r.limiter.mu.Unlock()
+	return r.limiter.stats
+}
+
+// wait takes a token, waiting until one is available or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// take the token now, so waiters queue up in order behind each other
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		if err := sleepContext(ctx, delay); err != nil {
+			// hand the token back for the next request
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	l.stats.Requests++
+	if delay > 0 {
+		l.stats.Delayed++
+		l.stats.TotalWait += delay
+		if delay > l.stats.MaxWait {
+			l.stats.MaxWait = delay
+		}
+	}
+	l.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRateLimit(20, 2))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := testClient.All(AllOptions{}); err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	// the burst of 2 passes straight away, the next 2 wait for 50ms each
+	if elapsed < 90*time.Millisecond {
+		t.Fatalf("got elapsed %v; want at least 90ms", elapsed)
+	}
+
+	stats := testClient.RateLimitStats()
+	if stats.Requests != 4 || stats.Delayed != 2 {
+		t.Fatalf("got stats %+v; want 4 requests and 2 delayed", stats)
+	}
+
+	if stats.TotalWait <= 0 || stats.MaxWait <= 0 || stats.MaxWait > stats.TotalWait {
+		t.Fatalf("got stats %+v; want positive waits", stats)
+	}
+}
+
+func TestRateLimitContextCancel(t *testing.T) {
+	testClient := New("TEST_API_KEY", WithRateLimit(0.1, 1))
+
+	// use up the burst
+	if err := testClient.limiter.wait(context.Background()); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := testClient.AllContext(ctx, AllOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got err %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	if stats := testClient.RateLimitStats(); stats.Requests != 1 {
+		t.Fatalf("got stats %+v; want 1 request", stats)
+	}
+}
+
+func TestRateLimitStatsWithoutLimiter(t *testing.T) {
+	if stats := New("TEST_API_KEY").RateLimitStats(); stats != (RateLimitStats{}) {
+		t.Fatalf("got stats %+v; want zero", stats)
+	}
+}
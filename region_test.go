@@ -39,7 +39,7 @@ func TestRegionErrorUrl(t *testing.T) {
 		Region: "Oceania",
 	})
 
-	wantErr := `Get "not%20a%20url/region/Oceania?access_key=TEST_API_KEY&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/region/Oceania?access_key=REDACTED&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
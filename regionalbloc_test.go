@@ -39,7 +39,7 @@ func TestRegionalBlocErrorUrl(t *testing.T) {
 		RegionalBloc: "PA",
 	})
 
-	wantErr := `Get "not%20a%20url/regionalbloc/PA?access_key=TEST_API_KEY&fields=": unsupported protocol scheme ""`
+	wantErr := `Get "not%20a%20url/regionalbloc/PA?access_key=REDACTED&fields=": unsupported protocol scheme ""`
 
 	if gotErr == nil || gotErr.Error() != wantErr {
 		t.Fatalf("got %s; want %s", gotErr, wantErr)
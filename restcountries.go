@@ -1,81 +1,77 @@
 package restcountries
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 )
 
 // Country represents a Country from the API
 // A slice of Country is returned by the methods which return countries, e.g. All and Name
 type Country struct {
-	Name           string    `json:"name"`
-	TopLevelDomain []string  `json:"topLevelDomain"`
-	Alpha2Code     string    `json:"alpha2Code"`
-	Alpha3Code     string    `json:"alpha3Code"`
-	CallingCodes   []string  `json:"callingCodes"`
-	Capital        string    `json:"capital"`
-	AltSpellings   []string  `json:"altSpellings"`
-	Region         string    `json:"region"`
-	Subregion      string    `json:"subregion"`
-	Population     int       `json:"population"`
-	Latlng         []float64 `json:"latlng"`
-	Demonym        string    `json:"demonym"`
-	Area           float64   `json:"area"`
-	Gini           float64   `json:"gini"`
-	Timezones      []string  `json:"timezones"`
-	Borders        []string  `json:"borders"`
-	NativeName     string    `json:"nativeName"`
-	NumericCode    string    `json:"numericCode"`
-	Currencies     []struct {
-		Code   string `json:"code"`
-		Name   string `json:"name"`
-		Symbol string `json:"symbol"`
-	} `json:"currencies"`
-	Languages []struct {
-		Iso6391    string `json:"iso639_1"`
-		Iso6392    string `json:"iso639_2"`
-		Name       string `json:"name"`
-		NativeName string `json:"nativeName"`
-	} `json:"languages"`
-	Translations struct {
-		De string `json:"de"`
-		Es string `json:"es"`
-		Fr string `json:"fr"`
-		Ja string `json:"ja"`
-		It string `json:"it"`
-		Br string `json:"br"`
-		Pt string `json:"pt"`
-		Nl string `json:"nl"`
-		Hr string `json:"hr"`
-		Fa string `json:"fa"`
-	} `json:"translations"`
-	Flag          string `json:"flag"`
-	RegionalBlocs []struct {
-		Acronym       string   `json:"acronym"`
-		Name          string   `json:"name"`
-		OtherAcronyms []string `json:"otherAcronyms"`
-		OtherNames    []string `json:"otherNames"`
-	} `json:"regionalBlocs"`
-	Cioc string `json:"cioc"`
-}
-
-type apiError struct {
-	Status  int16  `json:"status"`
-	Message string `json:"message"`
+	Name           string         `json:"name"`
+	TopLevelDomain []string       `json:"topLevelDomain"`
+	Alpha2Code     string         `json:"alpha2Code"`
+	Alpha3Code     string         `json:"alpha3Code"`
+	CallingCodes   []string       `json:"callingCodes"`
+	Capital        string         `json:"capital"`
+	AltSpellings   []string       `json:"altSpellings"`
+	Region         string         `json:"region"`
+	Subregion      string         `json:"subregion"`
+	Population     int            `json:"population"`
+	Latlng         []float64      `json:"latlng"`
+	Demonym        string         `json:"demonym"`
+	Area           float64        `json:"area"`
+	Gini           float64        `json:"gini"`
+	Timezones      []string       `json:"timezones"`
+	Borders        []string       `json:"borders"`
+	NativeName     string         `json:"nativeName"`
+	NumericCode    string         `json:"numericCode"`
+	Currencies     []Currency     `json:"currencies"`
+	Languages      []Language     `json:"languages"`
+	Translations   Translations   `json:"translations"`
+	Flag           string         `json:"flag"`
+	RegionalBlocs  []RegionalBloc `json:"regionalBlocs"`
+	Cioc           string         `json:"cioc"`
 }
 
 // RestCountries represents an app/client using the API
+// A client is configured once by New and is safe for concurrent use by multiple goroutines
 type RestCountries struct {
-	apiRoot string
-	timeout time.Duration
-	apiKey  string
+	apiRoot   string
+	timeout   time.Duration
+	apiKey    string
+	client    Doer
+	userAgent string
+
+	notFoundError bool
+	retry         RetryPolicy
+	limiter       *rateLimiter
+	cache         Cache
+	cacheTTL      time.Duration
+	flight        flightGroup
+	provider      Provider
+	backend       Backend
+	failover      *failover
+	breaker       *circuitBreaker
+	middleware    []Middleware
+	hooks         []ResponseHook
+	lookupHooks   []LookupHook
+	cacheHooks    []CacheHook
+	logger        *slog.Logger
+	apiKeyHeader  string
 }
 
-// httpClient is used for mocking the http client
-type httpClient interface {
+// Doer sends HTTP requests. *http.Client satisfies it, as does anything wrapping a custom RoundTripper, proxy or instrumentation
+type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
@@ -133,429 +129,487 @@ type CodesOptions struct {
 	Fields []string
 }
 
+// QueryOptions represents options for the Query() method
+type QueryOptions struct {
+	Path   string     // the path under the API root, e.g. /subregion/Northern Europe
+	Params url.Values // extra query parameters
+	Fields []string
+}
+
 // New creates and returns a new instance of the client
-func New(apiKey string) *RestCountries {
-	return &RestCountries{
-		apiRoot: "https://api.countrylayer.com/v2",
-		timeout: 0,
-		apiKey:  apiKey,
+// The optional opts configure the client, e.g. New(apiKey, WithBaseURL(url), WithTimeout(10*time.Second))
+func New(apiKey string, opts ...Option) *RestCountries {
+	r := &RestCountries{
+		timeout:  0,
+		apiKey:   apiKey,
+		client:   http.DefaultClient,
+		provider: Countrylayer,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.apiRoot == "" {
+		r.apiRoot = r.provider.BaseURL()
 	}
+
+	if r.apiKeyHeader != "" && r.apiKey != "" {
+		r.client = &headerDoer{next: r.client, name: r.apiKeyHeader, value: r.apiKey}
+	}
+
+	r.client = chain(r.client, r.middleware)
+
+	if r.userAgent != "" {
+		r.client = &userAgentDoer{next: r.client, userAgent: r.userAgent}
+	}
+
+	return r
+}
+
+// NewWithClient creates and returns a new instance of the client which sends every request through the given client
+// It is shorthand for New(apiKey, WithHTTPClient(client))
+func NewWithClient(apiKey string, client Doer) *RestCountries {
+	return New(apiKey, WithHTTPClient(client))
 }
 
 // SetApiRoot overrides the API root url - used for unit testing
+//
+// Deprecated: use New with WithBaseURL. SetApiRoot must not be called while requests are in flight
 func (r *RestCountries) SetApiRoot(url string) {
 	r.apiRoot = url
 }
 
 // SetTimeout overrides the HTTP clent timeout
+// The timeout applies to each request, including reading the response, on top of any timeout set on a custom client
+//
+// Deprecated: use New with WithTimeout. SetTimeout must not be called while requests are in flight
 func (r *RestCountries) SetTimeout(timeout time.Duration) {
 	r.timeout = timeout
 }
 
-// All method returns all countries
-// The optional AllOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) All(options AllOptions) ([]Country, error) {
-
-	fields := processFields(options.Fields)
+// get makes a GET request for url, which answers req, with the client's Doer, retrying transient failures according to the retry policy
+// header holds extra request headers and may be nil. With stream, the body of the response is left unread for the caller to close
+func (r *RestCountries) get(ctx context.Context, url string, req Request, header http.Header, stream bool) (*response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := r.getOnce(ctx, url, req, header, stream)
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(r.apiRoot+"/all?access_key="+url.QueryEscape(r.apiKey)+"&fields="+url.QueryEscape(fields), myClient)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
+		if attempt >= r.retry.MaxAttempts || ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+			return resp, err
+		}
 
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
+		if err != nil && !retryableError(err) {
+			return resp, err
 		}
 
-		if basicResponse.Status == 404 {
-			return countries, nil
+		delay := r.retry.backoff(attempt)
+		if err == nil {
+			// a status which the provider says means no countries matched is an answer, however often it is asked
+			if !r.retry.retryableStatus(resp.status) || r.provider.NotFound(req, resp.status) {
+				return resp, nil
+			}
+			if after, ok := retryAfter(resp.header, time.Now()); ok && after > delay {
+				// waiting longer than the policy allows or than the caller can wait would only delay the error
+				if !r.retry.canWait(ctx, after) {
+					return resp, nil
+				}
+				delay = after
+			}
+			if resp.body != nil {
+				resp.body.Close()
+			}
 		}
-		return nil, errors.New(basicResponse.Message)
 
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return nil, sleepErr
+		}
 	}
-
-	return countries, nil
 }
 
-// Name method searches countries by name
-// The optional NameOptions.FullText boolean when true, will search for an exact match. Otherwise, partial matches are returned
-// The optional NameOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) Name(options NameOptions) ([]Country, error) {
-
-	if options.Name == "" {
-		return nil, errors.New("Search term is empty")
-	}
-
-	fields := processFields(options.Fields)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/name/" + options.Name // this encodes the user input properly with %20 for space and others
-
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	if options.FullText {
-		params.Add("fullText", "true")
+// getOnce makes a single GET request for url with the client's Doer, waiting for the rate limiter and applying the configured timeout
+// The circuit breaker, if any, is checked first and told the outcome. An error status which the provider says means req
+// matched no countries, e.g. the 500 of the Countrylayer codes lookup, is an answer rather than a failure
+func (r *RestCountries) getOnce(ctx context.Context, url string, req Request, header http.Header, stream bool) (*response, error) {
+	if r.breaker == nil {
+		return r.send(ctx, url, header, stream)
 	}
-	base.RawQuery = params.Encode()
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
-
-	if err != nil {
+	if err := r.breaker.allow(); err != nil {
 		return nil, err
 	}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
-
-		if basicResponse.Status == 404 {
-			return countries, nil
-		}
-		return nil, errors.New(basicResponse.Message)
-
+	resp, err := r.send(ctx, url, header, stream)
+	if err != nil && ctx.Err() != nil {
+		// the caller gave up, which says nothing about the API
+		r.breaker.cancel()
+	} else {
+		r.breaker.record(err != nil || (resp.status >= http.StatusInternalServerError && !r.provider.NotFound(req, resp.status)))
 	}
 
-	return countries, nil
+	return resp, err
 }
 
-// Capital method searches countries by capital city using a partial match
-// The optional CapitalOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) Capital(options CapitalOptions) ([]Country, error) {
-
-	if options.Capital == "" {
-		return nil, errors.New("Search term is empty")
+// send waits for the rate limiter and makes the request with the configured timeout, then calls the response hooks
+// The timeout of a streamed response lasts until its body is closed
+func (r *RestCountries) send(ctx context.Context, url string, header http.Header, stream bool) (*response, error) {
+	if r.limiter != nil {
+		if err := r.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	fields := processFields(options.Fields)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/capital/" + options.Capital // this encodes the user input properly with %20 for space and others
+	reqCtx, cancel := ctx, context.CancelFunc(func() {})
+	if r.timeout > 0 {
+		reqCtx, cancel = context.WithTimeout(ctx, r.timeout)
+	}
 
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
+	r.debug(ctx, "restcountries request", slog.String("url", RedactURL(url)))
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
+	start := time.Now()
+	var resp *response
+	var err error
+	if stream {
+		resp, err = openUrl(reqCtx, url, header, r.client)
+	} else {
+		resp, err = getUrlContent(reqCtx, url, header, r.client)
+	}
+	// a custom Doer may put the url or the key header in its own error, which the hooks and the log must not see
+	err = redactKey(err, r.apiKey)
+	r.onResponse(ctx, url, start, resp, err)
 
 	if err != nil {
-		return nil, err
+		r.debug(ctx, "restcountries request failed", slog.String("url", RedactURL(url)),
+			slog.Duration("duration", time.Since(start)), slog.Any("error", err))
+	} else {
+		r.debug(ctx, "restcountries response", slog.String("url", RedactURL(url)), slog.Int("status", resp.status),
+			slog.Duration("duration", time.Since(start)), slog.Int("bytes", len(resp.content)), slog.Bool("streamed", stream))
 	}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
-
-		if basicResponse.Status == 404 {
-			return countries, nil
-		}
-		return nil, errors.New(basicResponse.Message)
-
+	if err == nil && stream {
+		resp.body = &cancelBody{ReadCloser: resp.body, cancel: cancel}
+	} else {
+		cancel()
 	}
 
-	return countries, nil
+	return resp, err
 }
 
-// Currency method searches countries by currency code using an exact match
-// The optional CurrencyOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) Currency(options CurrencyOptions) ([]Country, error) {
-
-	if options.Currency == "" {
-		return nil, errors.New("Search term is empty")
+// debug logs msg at debug level, if a logger is set with WithLogger
+func (r *RestCountries) debug(ctx context.Context, msg string, attrs ...slog.Attr) {
+	if r.logger != nil {
+		r.logger.LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
 	}
+}
 
-	fields := processFields(options.Fields)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/currency/" + options.Currency // this encodes the user input properly with %20 for space and others
+// fetchCountries gets the countries for url, which answers req, from the cache when one is set and the call's cache mode allows it
+func (r *RestCountries) fetchCountries(ctx context.Context, url string, req Request) ([]Country, error) {
+	mode := cacheModeFrom(ctx)
+	key := ""
+	if r.cache != nil && mode != CacheBypass {
+		key = cacheKey(url)
+	}
 
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
+	if key != "" && mode != CacheRefresh {
+		if content, ok := r.cache.Get(key); ok {
+			countries, err := r.decodeCountries(&response{content: content, status: http.StatusOK}, url, req)
+			if err == nil {
+				r.debug(ctx, "restcountries cache hit", slog.String("url", RedactURL(url)))
+				r.onCache(ctx, url, true)
+				return countries, nil
+			}
+		}
+		r.onCache(ctx, url, false)
+	}
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
+	// when no cache keeps the body and no response hook sees it, the countries are decoded from the body as it arrives
+	provider, decode := r.provider.(StreamingProvider)
+	decode = decode && key == "" && len(r.hooks) == 0
 
-	if err != nil {
-		return nil, err
+	// identical concurrent requests share one network call, and each caller gets its own copy of the countries
+	// Only the leader caches the response, so a caller which caches it never shares the call of one which doesn't
+	flightKey := cacheKey(url)
+	if key == "" {
+		flightKey += " uncached"
 	}
+	for {
+		resp, leader, err := r.flight.do(ctx, flightKey, func() (*response, error) {
+			if decode {
+				return r.fetchDecoded(ctx, url, req, provider)
+			}
+			return r.fetchResponse(ctx, url, req, key, mode)
+		})
+
+		if err != nil {
+			// the request was made with the context of another caller, so it is retried if only that context was cancelled
+			if !leader && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+				continue
+			}
+			return nil, err
+		}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
+		if resp.decoded {
+			if leader {
+				return resp.countries, nil
+			}
+			return cloneCountries(resp.countries), nil
 		}
 
-		if basicResponse.Status == 404 || basicResponse.Status == 400 { // 400 is returned for invalid search values
-			return countries, nil
+		countries, err := r.decodeCountries(resp, url, req)
+
+		// only a response which decoded into countries is cached, never an error or a not found
+		if leader && key != "" && err == nil && countries != nil && resp.status < http.StatusMultipleChoices {
+			if validatorCache, ok := r.cache.(ValidatorCache); ok {
+				validatorCache.SetWithValidators(key, resp.content, r.cacheTTL, validatorsFrom(resp.header))
+			} else {
+				r.cache.Set(key, resp.content, r.cacheTTL)
+			}
 		}
-		return nil, errors.New(basicResponse.Message)
 
+		return countries, err
 	}
-
-	return countries, nil
 }
 
-// Language method searches countries by language code using an exact match
-// The optional LanguageOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) Language(options LanguageOptions) ([]Country, error) {
-
-	if options.Language == "" {
-		return nil, errors.New("Search term is empty")
+// fetchResponse gets the response for url, which answers req, from the API
+// When the cache keeps validators, a stale entry for key is revalidated with a conditional request and reused if it is not modified
+func (r *RestCountries) fetchResponse(ctx context.Context, url string, req Request, key string, mode CacheMode) (*response, error) {
+	validatorCache, _ := r.cache.(ValidatorCache)
+	var stale []byte
+	var staleValidators Validators
+	var header http.Header
+	if key != "" && mode == CacheDefault && validatorCache != nil {
+		if content, validators, ok := validatorCache.GetStale(key); ok && validators != (Validators{}) {
+			stale, staleValidators, header = content, validators, validators.header()
+		}
 	}
 
-	fields := processFields(options.Fields)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/lang/" + options.Language // this encodes the user input properly with %20 for space and others
-
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
-
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
-
+	resp, err := r.get(ctx, url, req, header, false)
 	if err != nil {
 		return nil, err
 	}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
-
-		if basicResponse.Status == 404 {
-			return countries, nil
+	if stale != nil && resp.status == http.StatusNotModified {
+		// keep the stale validators if the 304 response didn't repeat them
+		notModifiedHeader := resp.header.Clone()
+		if validatorsFrom(notModifiedHeader) == (Validators{}) {
+			notModifiedHeader = staleValidators.response()
 		}
-		return nil, errors.New(basicResponse.Message)
-
+		resp = &response{content: stale, status: http.StatusOK, header: notModifiedHeader}
 	}
 
-	return countries, nil
+	return resp, nil
 }
 
-// Region method searches countries by region using an exact match
-// The optional RegionOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) Region(options RegionOptions) ([]Country, error) {
-
-	if options.Region == "" {
-		return nil, errors.New("Search term is empty")
-	}
-
-	fields := processFields(options.Fields)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/region/" + options.Region // this encodes the user input properly with %20 for space and others
-
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
-
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
-
+// fetchDecoded gets the response for url, which answers req, from the API, decoding an array of countries with provider
+// as it arrives, so the body is never held in memory whole. Any other body, e.g. an error, is small and is read into the
+// content of the response for decodeCountries
+func (r *RestCountries) fetchDecoded(ctx context.Context, url string, req Request, provider StreamingProvider) (*response, error) {
+	resp, err := r.get(ctx, url, req, nil, true)
 	if err != nil {
 		return nil, err
 	}
-
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
+	defer resp.body.Close()
+
+	body := bufio.NewReader(resp.body)
+	resp.body = nil
+	if resp.status >= http.StatusMultipleChoices || peekJSONByte(body) != '[' {
+		resp.content, err = io.ReadAll(body)
+		if err != nil {
+			return nil, err
 		}
+		return resp, nil
+	}
 
-		if basicResponse.Status == 404 {
-			return countries, nil
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	resp.countries = []Country{}
+	for dec.More() {
+		country, err := provider.DecodeNext(dec)
+		if err != nil {
+			return nil, err
 		}
-		return nil, errors.New(basicResponse.Message)
-
+		resp.countries = append(resp.countries, country)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
 	}
 
-	return countries, nil
+	resp.decoded = true
+	return resp, nil
 }
 
-// RegionalBloc method searches countries by regional Bloc using an exact match
-// The optional RegionalBlocOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) RegionalBloc(options RegionalBlocOptions) ([]Country, error) {
-
-	if options.RegionalBloc == "" {
-		return nil, errors.New("Search term is empty")
+// cloneCountries returns a copy of countries which shares no slices or maps with them
+func cloneCountries(countries []Country) []Country {
+	clones := make([]Country, len(countries))
+	for i, c := range countries {
+		c.TopLevelDomain = slices.Clone(c.TopLevelDomain)
+		c.CallingCodes = slices.Clone(c.CallingCodes)
+		c.AltSpellings = slices.Clone(c.AltSpellings)
+		c.Latlng = slices.Clone(c.Latlng)
+		c.Timezones = slices.Clone(c.Timezones)
+		c.Borders = slices.Clone(c.Borders)
+		c.Currencies = slices.Clone(c.Currencies)
+		c.Languages = slices.Clone(c.Languages)
+		c.Translations = maps.Clone(c.Translations)
+		c.RegionalBlocs = slices.Clone(c.RegionalBlocs)
+		for j, bloc := range c.RegionalBlocs {
+			c.RegionalBlocs[j].OtherAcronyms = slices.Clone(bloc.OtherAcronyms)
+			c.RegionalBlocs[j].OtherNames = slices.Clone(bloc.OtherNames)
+		}
+		clones[i] = c
 	}
+	return clones
+}
 
-	fields := processFields(options.Fields)
+// decodeCountries decodes the response content from url into countries with the provider, or into an *APIError when the API returned an error
+// An error status which the provider says means req matched no countries gives an empty slice, unless WithNotFoundError is set
+func (r *RestCountries) decodeCountries(resp *response, url string, req Request) ([]Country, error) {
+	content := resp.content
 
-	base, _ := url.Parse(r.apiRoot)
+	// the status and the first byte tell countries from an error body, so the body is decoded once
+	if resp.status < http.StatusMultipleChoices && firstJSONByte(content) == '[' {
+		return r.provider.Decode(content)
+	}
 
-	base.Path += "/regionalbloc/" + options.RegionalBloc // this encodes the user input properly with %20 for space and others
+	var basicResponse apiError
+	if firstJSONByte(content) != '{' || json.Unmarshal(content, &basicResponse) != nil || (basicResponse.Status == 0 && basicResponse.Error == nil) {
+		// not an error body, so the provider decodes it or says what is wrong with it
+		return r.provider.Decode(content)
+	}
 
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
+	apiErr := &APIError{
+		StatusCode: basicResponse.Status,
+		Message:    basicResponse.Message,
+		Path:       requestPath(r.apiRoot, url),
+		Body:       content,
+	}
+	if basicResponse.Error != nil {
+		apiErr.Code = basicResponse.Error.Code
+		apiErr.Type = basicResponse.Error.Type
+		apiErr.Message = basicResponse.Error.Info
+	}
+	if apiErr.StatusCode == 0 {
+		apiErr.StatusCode = resp.status
+	}
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
+	apiErr.notFound = r.provider.NotFound(req, apiErr.StatusCode)
 
-	if err != nil {
-		return nil, err
+	if apiErr.Is(ErrNotFound) && !r.notFoundError {
+		return nil, nil
 	}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
+	return nil, apiErr
+}
 
-		if basicResponse.Status == 404 {
-			return countries, nil
-		}
-		return nil, errors.New(basicResponse.Message)
+// All method returns all countries
+// The optional AllOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) All(options AllOptions) ([]Country, error) {
+	return r.AllContext(context.Background(), options)
+}
 
-	}
+// AllContext is like All but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) AllContext(ctx context.Context, options AllOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointAll, Fields: options.Fields})
+}
 
-	return countries, nil
+// Name method searches countries by name
+// The optional NameOptions.FullText boolean when true, will search for an exact match. Otherwise, partial matches are returned
+// The optional NameOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) Name(options NameOptions) ([]Country, error) {
+	return r.NameContext(context.Background(), options)
 }
 
-// CallingCode method searches countries by calling code using an exact match
-// The optional CallingCodeOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
-func (r *RestCountries) CallingCode(options CallingCodeOptions) ([]Country, error) {
+// NameContext is like Name but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) NameContext(ctx context.Context, options NameOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointName, Term: options.Name, FullText: options.FullText, Fields: options.Fields})
+}
 
-	if options.CallingCode == "" {
-		return nil, errors.New("Search term is empty")
-	}
+// Capital method searches countries by capital city using a partial match
+// The optional CapitalOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) Capital(options CapitalOptions) ([]Country, error) {
+	return r.CapitalContext(context.Background(), options)
+}
 
-	fields := processFields(options.Fields)
+// CapitalContext is like Capital but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) CapitalContext(ctx context.Context, options CapitalOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointCapital, Term: options.Capital, Fields: options.Fields})
+}
 
-	base, _ := url.Parse(r.apiRoot)
+// Currency method searches countries by currency code using an exact match
+// The optional CurrencyOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) Currency(options CurrencyOptions) ([]Country, error) {
+	return r.CurrencyContext(context.Background(), options)
+}
 
-	base.Path += "/callingcode/" + options.CallingCode // this encodes the user input properly with %20 for space and others
+// CurrencyContext is like Currency but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) CurrencyContext(ctx context.Context, options CurrencyOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointCurrency, Term: options.Currency, Fields: options.Fields})
+}
 
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	base.RawQuery = params.Encode()
+// Language method searches countries by language code using an exact match
+// The optional LanguageOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) Language(options LanguageOptions) ([]Country, error) {
+	return r.LanguageContext(context.Background(), options)
+}
 
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
+// LanguageContext is like Language but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) LanguageContext(ctx context.Context, options LanguageOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointLanguage, Term: options.Language, Fields: options.Fields})
+}
 
-	if err != nil {
-		return nil, err
-	}
+// Region method searches countries by region using an exact match
+// The optional RegionOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) Region(options RegionOptions) ([]Country, error) {
+	return r.RegionContext(context.Background(), options)
+}
 
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
+// RegionContext is like Region but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) RegionContext(ctx context.Context, options RegionOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointRegion, Term: options.Region, Fields: options.Fields})
+}
 
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
+// RegionalBloc method searches countries by regional Bloc using an exact match
+// The optional RegionalBlocOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) RegionalBloc(options RegionalBlocOptions) ([]Country, error) {
+	return r.RegionalBlocContext(context.Background(), options)
+}
 
-		if basicResponse.Status == 404 {
-			return countries, nil
-		}
-		return nil, errors.New(basicResponse.Message)
+// RegionalBlocContext is like RegionalBloc but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) RegionalBlocContext(ctx context.Context, options RegionalBlocOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointRegionalBloc, Term: options.RegionalBloc, Fields: options.Fields})
+}
 
-	}
+// CallingCode method searches countries by calling code using an exact match
+// The optional CallingCodeOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
+func (r *RestCountries) CallingCode(options CallingCodeOptions) ([]Country, error) {
+	return r.CallingCodeContext(context.Background(), options)
+}
 
-	return countries, nil
+// CallingCodeContext is like CallingCode but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) CallingCodeContext(ctx context.Context, options CallingCodeOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointCallingCode, Term: options.CallingCode, Fields: options.Fields})
 }
 
 // Codes method searches countries by country codes using an exact match
 // The optional CodesOptions.Fields allows filtering fields by specifying the fields you want, instead of all fields
 func (r *RestCountries) Codes(options CodesOptions) ([]Country, error) {
+	return r.CodesContext(context.Background(), options)
+}
 
-	if len(options.Codes) == 0 {
-		return nil, errors.New("Search term is empty")
-	}
-
-	fields := processFields(options.Fields)
-	codes := processCodes(options.Codes)
-
-	base, _ := url.Parse(r.apiRoot)
-
-	base.Path += "/alpha/"
-
-	params := url.Values{}
-	params.Add("access_key", r.apiKey)
-	params.Add("fields", fields)
-	params.Add("codes", codes)
-	base.RawQuery = params.Encode()
-
-	var myClient = &http.Client{Timeout: r.timeout}
-	content, err := getUrlContent(base.String(), myClient)
-
-	if err != nil {
-		return nil, err
-	}
-
-	var countries []Country
-	decodeErr := json.Unmarshal([]byte(content), &countries)
-	if decodeErr != nil {
-
-		var basicResponse apiError
-		basicResponseErr := json.Unmarshal([]byte(content), &basicResponse)
-		if basicResponseErr != nil {
-			return nil, decodeErr
-		}
-
-		// the api returns a 400 for a single code which doesn't match a country, or a 500 for a list of codes where one or more do not match
-		if basicResponse.Status == 404 || basicResponse.Status == 400 || basicResponse.Status == 500 {
-			return countries, nil
-		}
-		return nil, errors.New(basicResponse.Message)
+// CodesContext is like Codes but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) CodesContext(ctx context.Context, options CodesOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointCodes, Codes: options.Codes, Fields: options.Fields})
+}
 
-	}
+// Query method looks up countries at any path of the API, e.g. an endpoint the client has no method for
+// The request goes through the same pipeline as the other methods, with the API key, cache, retries and not-found handling
+// The optional QueryOptions.Params adds query parameters, and QueryOptions.Fields filters the fields
+func (r *RestCountries) Query(options QueryOptions) ([]Country, error) {
+	return r.QueryContext(context.Background(), options)
+}
 
-	return countries, nil
+// QueryContext is like Query but uses ctx for the HTTP request, cancelling it when ctx is done
+func (r *RestCountries) QueryContext(ctx context.Context, options QueryOptions) ([]Country, error) {
+	return r.Lookup(ctx, Request{Endpoint: EndpointQuery, Path: options.Path, Params: options.Params, Fields: options.Fields})
 }
@@ -0,0 +1,94 @@
+package restcountries
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// RestCountriesV3 is the provider for the restcountries.com v3.1 API, which needs no API key
+// It has no regional bloc or calling code lookups, which return ErrUnsupported
+// The language lookup takes an ISO 639-2 code or a language name, e.g. spa or spanish
+var RestCountriesV3 Provider = restCountriesV3{}
+
+type restCountriesV3 struct{}
+
+// restCountriesV3Endpoints are the endpoints of the API
+var restCountriesV3Endpoints = map[Endpoint]endpointConfig{
+	EndpointAll:      {path: "/all", notFound: notFoundStatuses},
+	EndpointName:     {path: "/name/", notFound: notFoundStatuses},
+	EndpointCapital:  {path: "/capital/", notFound: notFoundStatuses},
+	EndpointCurrency: {path: "/currency/", notFound: notFoundStatuses},
+	EndpointLanguage: {path: "/lang/", notFound: notFoundStatuses},
+	EndpointRegion:   {path: "/region/", notFound: notFoundStatuses},
+	EndpointCodes:    {path: "/alpha", notFound: invalidTermStatuses}, // a code which isn't 2 or 3 characters gives a 400
+	EndpointQuery:    {notFound: notFoundStatuses},
+}
+
+// restCountriesV3Fields maps the JSON names of the Country fields to the fields of the v3.1 API
+var restCountriesV3Fields = map[string]string{
+	"alpha2Code":     "cca2",
+	"alpha3Code":     "cca3",
+	"callingCodes":   "idd",
+	"demonym":        "demonyms",
+	"flag":           "flags",
+	"nativeName":     "name",
+	"numericCode":    "ccn3",
+	"regionalBlocs":  "",
+	"topLevelDomain": "tld",
+}
+
+func (restCountriesV3) BaseURL() string {
+	return "https://restcountries.com/v3.1"
+}
+
+func (restCountriesV3) URL(root string, apiKey string, req Request) (string, error) {
+	params := url.Values{}
+	if fields := restCountriesV3FieldList(req.Fields); fields != "" {
+		params.Add("fields", fields)
+	}
+	if req.FullText {
+		params.Add("fullText", "true")
+	}
+	if req.Endpoint == EndpointCodes {
+		params.Add("codes", strings.Join(req.Codes, ","))
+	}
+
+	return endpointURL(root, restCountriesV3Endpoints, req, params)
+}
+
+func (restCountriesV3) Decode(content []byte) ([]Country, error) {
+	var v3 []CountryV3
+	if err := json.Unmarshal(content, &v3); err != nil {
+		return nil, err
+	}
+
+	countries := make([]Country, len(v3))
+	for i, c := range v3 {
+		countries[i] = c.Country()
+	}
+
+	return countries, nil
+}
+
+func (restCountriesV3) NotFound(req Request, status int) bool {
+	return endpointNotFound(restCountriesV3Endpoints, req, status)
+}
+
+// restCountriesV3FieldList returns the comma separated v3.1 fields for the fields of Country
+func restCountriesV3FieldList(fields []string) string {
+	var out []string
+	seen := map[string]bool{}
+	for _, field := range fields {
+		name := lCFirst(field)
+		if v3, ok := restCountriesV3Fields[name]; ok {
+			name = v3
+		}
+		if name != "" && !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+
+	return strings.Join(out, ",")
+}
@@ -0,0 +1,118 @@
+package restcountries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const restCountriesV3Estonia = `[{
+	"name": {"common": "Estonia", "official": "Republic of Estonia", "nativeName": {"est": {"official": "Eesti Vabariik", "common": "Eesti"}}},
+	"tld": [".ee"], "cca2": "EE", "ccn3": "233", "cca3": "EST", "cioc": "EST",
+	"currencies": {"EUR": {"name": "Euro", "symbol": "€"}},
+	"idd": {"root": "+3", "suffixes": ["72"]},
+	"capital": ["Tallinn"], "altSpellings": ["EE", "Eesti"], "region": "Europe", "subregion": "Northern Europe",
+	"languages": {"est": "Estonian"},
+	"translations": {"deu": {"official": "Republik Estland", "common": "Estland"}, "por": {"official": "República da Estónia", "common": "Estónia"}},
+	"latlng": [59.0, 26.0], "borders": ["LVA", "RUS"], "area": 45227,
+	"demonyms": {"eng": {"f": "Estonian", "m": "Estonian"}},
+	"population": 1331057, "gini": {"2015": 32.7, "2018": 30.3}, "timezones": ["UTC+02:00"],
+	"flags": {"png": "https://flagcdn.com/w320/ee.png", "svg": "https://flagcdn.com/ee.svg"}
+}]`
+
+func TestRestCountriesV3(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		fmt.Fprint(w, restCountriesV3Estonia)
+	}))
+	defer server.Close()
+
+	testClient := New("", WithProvider(RestCountriesV3), WithBaseURL(server.URL))
+
+	result, err := testClient.Name(NameOptions{Name: "Estonia", FullText: true, Fields: []string{"Name", "Alpha2Code", "CallingCodes"}})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	wantURL := "/name/Estonia?fields=name%2Ccca2%2Cidd&fullText=true"
+	if gotURL != wantURL {
+		t.Fatalf("got url %s; want %s", gotURL, wantURL)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("got %d countries; want 1", len(result))
+	}
+
+	got := result[0]
+	if got.Name != "Estonia" || got.NativeName != "Eesti" || got.Capital != "Tallinn" || got.Alpha3Code != "EST" || got.NumericCode != "233" {
+		t.Fatalf("got %+v; want Estonia", got)
+	}
+
+	if !reflect.DeepEqual(got.CallingCodes, []string{"372"}) || got.Demonym != "Estonian" || got.Gini != 30.3 || got.Flag != "https://flagcdn.com/ee.svg" {
+		t.Fatalf("got %+v; want normalised fields", got)
+	}
+
+	if len(got.Currencies) != 1 || got.Currencies[0].Code != "EUR" || got.Currencies[0].Symbol != "€" {
+		t.Fatalf("got currencies %+v; want EUR", got.Currencies)
+	}
+
+	if len(got.Languages) != 1 || got.Languages[0].Iso6392 != "est" || got.Languages[0].Name != "Estonian" {
+		t.Fatalf("got languages %+v; want Estonian", got.Languages)
+	}
+
+	if got.Translations["de"] != "Estland" || got.Translations["pt"] != "Estónia" || got.Translations.Get("pt-BR") != "Estónia" {
+		t.Fatalf("got translations %+v; want de, pt and br", got.Translations)
+	}
+}
+
+func TestRestCountriesV3URL(t *testing.T) {
+	tests := []struct {
+		req     Request
+		want    string
+		wantErr error
+	}{
+		{req: Request{Endpoint: EndpointAll, Fields: []string{"Name", "Flag", "NativeName"}}, want: "https://restcountries.com/v3.1/all?fields=name%2Cflags"},
+		{req: Request{Endpoint: EndpointCodes, Codes: []string{"CO", "GB"}}, want: "https://restcountries.com/v3.1/alpha?codes=CO%2CGB"},
+		{req: Request{Endpoint: EndpointCurrency, Term: "EUR"}, want: "https://restcountries.com/v3.1/currency/EUR"},
+		{req: Request{Endpoint: EndpointLanguage, Term: "spa"}, want: "https://restcountries.com/v3.1/lang/spa"},
+		{req: Request{Endpoint: EndpointRegionalBloc, Term: "EU"}, wantErr: ErrUnsupported},
+		{req: Request{Endpoint: EndpointCallingCode, Term: "44"}, wantErr: ErrUnsupported},
+	}
+
+	for _, test := range tests {
+		got, err := RestCountriesV3.URL(RestCountriesV3.BaseURL(), "", test.req)
+
+		if test.wantErr != nil {
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("want err %v, got: %v", test.wantErr, err)
+			}
+			continue
+		}
+
+		if got != test.want {
+			t.Fatalf("want: %s, got: %s", test.want, got)
+		}
+	}
+}
+
+func TestRestCountriesV3NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "message": "Not Found"}`)
+	}))
+	defer server.Close()
+
+	result, err := New("", WithProvider(RestCountriesV3), WithBaseURL(server.URL)).Capital(CapitalOptions{Capital: "Atlantis"})
+	if err != nil || len(result) != 0 {
+		t.Fatalf("got %v, %v; want empty result", result, err)
+	}
+
+	_, err = New("", WithProvider(RestCountriesV3), WithBaseURL(server.URL), WithNotFoundError()).Capital(CapitalOptions{Capital: "Atlantis"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got err %v; want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,167 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how failed requests are retried
+// Every request made by the client is an idempotent GET, so retrying is always safe
+// A request is retried when it fails with a transient network error, e.g. a timeout or a refused or reset connection, or
+// the API responds with one of RetryableStatuses, unless the provider says the status means no countries matched
+// A permanent error, e.g. an invalid url or a TLS certificate which can't be verified, is never retried
+// Retries stop as soon as the request context is cancelled
+type RetryPolicy struct {
+	MaxAttempts       int           // the total number of attempts, including the first. 0 or 1 disables retries
+	BaseBackoff       time.Duration // the delay before the first retry, doubled for each further retry
+	MaxBackoff        time.Duration // the upper limit for the delay between retries. 0 means no limit
+	Jitter            float64       // the fraction, from 0 to 1, of each delay which is randomised to spread out retries
+	RetryableStatuses []int         // the HTTP statuses which are retried. When empty, 429, 500, 502, 503 and 504 are retried
+}
+
+// DefaultRetryPolicy is a sensible policy for use with WithRetry
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseBackoff: 200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+	Jitter:      0.5,
+}
+
+// defaultRetryableStatuses are the HTTP statuses retried when RetryPolicy.RetryableStatuses is empty
+var defaultRetryableStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// WithRetry retries requests which fail with a network error or a retryable status, according to policy
+// A Retry-After header sent by the API is honoured when it asks for a longer delay than the backoff. When it asks for
+// longer than MaxBackoff or than is left before the deadline of the request context, e.g. the hour of a quota which ran
+// out, the response is returned at once, so the call fails with ErrRateLimited for a 429
+func WithRetry(policy RetryPolicy) Option {
+	return func(r *RestCountries) {
+		r.retry = policy
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt, starting from 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
+
+// canWait reports whether the delay asked for by a Retry-After header is within MaxBackoff and ends before the deadline of ctx
+func (p RetryPolicy) canWait(ctx context.Context, delay time.Duration) bool {
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return false
+	}
+	if deadline, ok := ctx.Deadline(); ok && delay > time.Until(deadline) {
+		return false
+	}
+	return true
+}
+
+// retryableStatus reports whether a response with the HTTP status should be retried
+func (p RetryPolicy) retryableStatus(status int) bool {
+	statuses := p.RetryableStatuses
+	if len(statuses) == 0 {
+		statuses = defaultRetryableStatuses
+	}
+
+	for _, s := range statuses {
+		if status == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// retryableError reports whether a request which failed with err may succeed if it is made again: after a timeout, or a
+// network error such as a refused or reset connection, but not a mistake in the url, a DNS name which doesn't exist or
+// a TLS failure
+func retryableError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// a *url.Error is a net.Error whatever it wraps, so only what it wraps tells whether the failure is transient
+		err = urlErr.Err
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or an HTTP date
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if date.Before(now) {
+			return 0, true
+		}
+		return date.Sub(now), true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the delay, returning early with the context error if ctx is done first
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
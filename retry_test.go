@@ -0,0 +1,343 @@
+package restcountries
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryTransientStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+	}))
+
+	result, err := testClient.Name(NameOptions{Name: "France"})
+	if err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+
+	if len(result) != 1 || result[0].Name != "France" {
+		t.Fatalf("got %v; want France", result)
+	}
+
+	if calls != 3 {
+		t.Fatalf("got %d calls; want 3", calls)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"status": 429, "message": "Too Many Requests"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+		MaxAttempts: 2,
+		BaseBackoff: time.Millisecond,
+	}))
+
+	_, err := testClient.All(AllOptions{})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got err %v; want %v", err, ErrRateLimited)
+	}
+
+	if calls != 2 {
+		t.Fatalf("got %d calls; want 2", calls)
+	}
+}
+
+func TestRetryNotRetryableStatus(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"status": 404, "message": "Not Found"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(DefaultRetryPolicy))
+
+	result, err := testClient.Name(NameOptions{Name: "Nowhere"})
+	if err != nil || len(result) != 0 {
+		t.Fatalf("got %v, %v; want empty result", result, err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("got %d calls; want 1", calls)
+	}
+}
+
+func TestRetryNotFoundServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// the Countrylayer API's answer when one of the codes doesn't match
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"status": 500, "message": "Internal Server Error"}`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+	}))
+
+	result, err := testClient.Codes(CodesOptions{Codes: []string{"CO", "XX"}})
+	if err != nil || len(result) != 0 {
+		t.Fatalf("got %v, %v; want empty result", result, err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("got %d calls; want 1", calls)
+	}
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls int32
+	}{
+		{"reset connection", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, 2},
+		{"unexpected EOF", io.ErrUnexpectedEOF, 2},
+		{"permanent", errors.New("unsupported protocol scheme"), 1},
+		{"TLS", &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}, 1},
+	}
+
+	for _, test := range tests {
+		var calls int32
+		mockedClient := &ClientMock{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					return nil, test.err
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`[{"name":"France"}]`)),
+				}, nil
+			},
+		}
+
+		testClient := New("TEST_API_KEY", WithHTTPClient(mockedClient), WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			BaseBackoff: time.Millisecond,
+		}))
+		testClient.All(AllOptions{})
+
+		if calls != test.wantCalls {
+			t.Errorf("%s: got %d calls; want %d", test.name, calls, test.wantCalls)
+		}
+	}
+}
+
+func TestRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"refused connection", &url.Error{Op: "Get", URL: "https://example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}, true},
+		{"timeout", &url.Error{Op: "Get", URL: "https://example.com", Err: context.DeadlineExceeded}, true},
+		{"closed connection", &url.Error{Op: "Get", URL: "https://example.com", Err: io.EOF}, true},
+		{"DNS timeout", &net.OpError{Op: "dial", Err: &net.DNSError{Name: "example.com", IsTimeout: true}}, true},
+		{"unknown host", &net.OpError{Op: "dial", Err: &net.DNSError{Name: "example.invalid", IsNotFound: true}}, false},
+		{"unsupported protocol scheme", &url.Error{Op: "Get", URL: "not a url", Err: errors.New(`unsupported protocol scheme ""`)}, false},
+		{"certificate", &url.Error{Op: "Get", URL: "https://example.com", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}, false},
+		{"cancelled", context.Canceled, false},
+	}
+
+	for _, test := range tests {
+		if got := retryableError(test.err); got != test.want {
+			t.Errorf("%s: got %v; want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+		MaxAttempts: 5,
+		BaseBackoff: time.Minute,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := testClient.AllContext(ctx, AllOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got err %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	if calls != 1 {
+		t.Fatalf("got %d calls; want 1", calls)
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"status": 429, "message": "Too Many Requests"}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		maxBackoff time.Duration
+		timeout    time.Duration
+	}{
+		{"longer than MaxBackoff", time.Second, 0},
+		{"longer than the deadline", 0, 2 * time.Second},
+		{"both", time.Second, 2 * time.Second},
+	}
+
+	for _, test := range tests {
+		atomic.StoreInt32(&calls, 0)
+		testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+			MaxAttempts: 3,
+			BaseBackoff: time.Millisecond,
+			MaxBackoff:  test.maxBackoff,
+		}))
+
+		ctx := context.Background()
+		if test.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, test.timeout)
+			defer cancel()
+		}
+
+		start := time.Now()
+		_, err := testClient.AllContext(ctx, AllOptions{})
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, ErrRateLimited) {
+			t.Errorf("%s: got err %v; want an *APIError matching %v", test.name, err, ErrRateLimited)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: took %v; want the 429 at once", test.name, elapsed)
+		}
+		if calls != 1 {
+			t.Errorf("%s: got %d calls; want 1", test.name, calls)
+		}
+	}
+}
+
+func TestRetryAfterHonoured(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithRetry(RetryPolicy{
+		MaxAttempts: 2,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  2 * time.Second,
+	}))
+
+	start := time.Now()
+	if _, err := testClient.All(AllOptions{}); err != nil {
+		t.Fatalf("got err %v; want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("took %v; want the retry after a second", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls; want 2", calls)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 4, want: 800 * time.Millisecond},
+		{attempt: 5, want: time.Second},
+		{attempt: 50, want: time.Second},
+	}
+
+	for _, test := range tests {
+		if got := policy.backoff(test.attempt); got != test.want {
+			t.Fatalf("attempt %d: got %v; want %v", test.attempt, got, test.want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("got %v; want between 50ms and 100ms", got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{value: "", wantOk: false},
+		{value: "3", want: 3 * time.Second, wantOk: true},
+		{value: "Tue, 01 Jun 2021 12:00:10 GMT", want: 10 * time.Second, wantOk: true},
+		{value: "Tue, 01 Jun 2021 11:00:00 GMT", want: 0, wantOk: true},
+		{value: "soon", wantOk: false},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		if test.value != "" {
+			header.Set("Retry-After", test.value)
+		}
+
+		got, gotOk := retryAfter(header, now)
+		if got != test.want || gotOk != test.wantOk {
+			t.Fatalf("%q: got %v, %v; want %v, %v", test.value, got, gotOk, test.want, test.wantOk)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package restcountries
+
+import (
+	"strings"
+)
+
+// Predicate reports whether a country matches a condition, see Select
+type Predicate func(Country) bool
+
+// Region matches countries in region, e.g. Europe, ignoring case
+func Region(region string) Predicate {
+	return func(c Country) bool {
+		return strings.EqualFold(c.Region, region)
+	}
+}
+
+// Subregion matches countries in subregion, e.g. Northern Europe, ignoring case
+func Subregion(subregion string) Predicate {
+	return func(c Country) bool {
+		return strings.EqualFold(c.Subregion, subregion)
+	}
+}
+
+// HasCurrency matches countries using the currency with an ISO 4217 code, e.g. EUR, ignoring case
+func HasCurrency(code string) Predicate {
+	return func(c Country) bool {
+		for _, currency := range c.Currencies {
+			if strings.EqualFold(currency.Code, code) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SpeaksLanguage matches countries where the language with an ISO 639-1 or ISO 639-2 code is spoken, e.g. de or deu
+func SpeaksLanguage(code string) Predicate {
+	return func(c Country) bool {
+		for _, language := range c.Languages {
+			if language.Is(code) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// InBloc matches countries in the regional bloc with an acronym, e.g. EU, see RegionalBloc.Is
+func InBloc(acronym string) Predicate {
+	return func(c Country) bool {
+		for _, bloc := range c.RegionalBlocs {
+			if bloc.Is(acronym) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Range is a range of numbers, including its bounds, see AtLeast, AtMost and Between
+type Range struct {
+	min, max       float64
+	hasMin, hasMax bool
+}
+
+// AtLeast is the range of numbers from min up
+func AtLeast(min float64) Range {
+	return Range{min: min, hasMin: true}
+}
+
+// AtMost is the range of numbers up to max
+func AtMost(max float64) Range {
+	return Range{max: max, hasMax: true}
+}
+
+// Between is the range of numbers from min to max
+func Between(min, max float64) Range {
+	return Range{min: min, max: max, hasMin: true, hasMax: true}
+}
+
+// Contains reports whether x is in the range
+func (r Range) Contains(x float64) bool {
+	return (!r.hasMin || x >= r.min) && (!r.hasMax || x <= r.max)
+}
+
+// inRange matches countries whose value for the numeric order by is known and in r
+func inRange(by By, r Range) Predicate {
+	return func(c Country) bool {
+		x, known := by.value(c)
+		return known && r.Contains(x)
+	}
+}
+
+// Population matches countries with a population in r, e.g. Population(AtLeast(5_000_000))
+func Population(r Range) Predicate {
+	return inRange(ByPopulation, r)
+}
+
+// Area matches countries with an area in km² in r, never those of unknown area
+func Area(r Range) Predicate {
+	return inRange(ByArea, r)
+}
+
+// Density matches countries with a population per km² in r, never those of unknown area
+func Density(r Range) Predicate {
+	return inRange(ByDensity, r)
+}
+
+// Gini matches countries with a Gini coefficient in r, never those without one
+func Gini(r Range) Predicate {
+	return inRange(ByGini, r)
+}
+
+// And matches countries which match every one of predicates, or every country when there are none
+func And(predicates ...Predicate) Predicate {
+	return func(c Country) bool {
+		for _, predicate := range predicates {
+			if !predicate(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches countries which match any of predicates, or no country when there are none
+func Or(predicates ...Predicate) Predicate {
+	return func(c Country) bool {
+		for _, predicate := range predicates {
+			if predicate(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches countries which don't match predicate
+func Not(predicate Predicate) Predicate {
+	return func(c Country) bool {
+		return !predicate(c)
+	}
+}
+
+// Selection is a query over countries held in memory, e.g. a result of All or of an Offline, for conditions the API
+// can't combine in one lookup, e.g. European countries using the euro with more than 5 million people, by area
+// Each method returns a new Selection, so a Selection can be reused as the base of others
+//
+//	page := restcountries.Select(countries).
+//		Where(restcountries.Region("Europe"), restcountries.HasCurrency("EUR")).
+//		Where(restcountries.Population(restcountries.AtLeast(5_000_000))).
+//		SortBy(restcountries.ByArea.Desc()).
+//		Page(2, 10).
+//		Countries()
+type Selection struct {
+	countries []Country
+	where     []Predicate
+	by        []By
+	offset    int
+	limit     int // no limit when 0
+	fields    []string
+}
+
+// Select starts a Selection of all of countries
+func Select(countries []Country) Selection {
+	return Selection{countries: countries}
+}
+
+// Where returns the Selection keeping only countries which match every one of predicates, as well as the conditions
+// of earlier calls
+func (s Selection) Where(predicates ...Predicate) Selection {
+	s.where = append(s.where[:len(s.where):len(s.where)], predicates...)
+	return s
+}
+
+// SortBy returns the Selection sorted by the given orders, as SortCountries sorts, instead of in the original order
+func (s Selection) SortBy(by ...By) Selection {
+	s.by = append([]By(nil), by...)
+	return s
+}
+
+// Offset returns the Selection skipping the first n matching countries
+func (s Selection) Offset(n int) Selection {
+	s.offset = n
+	return s
+}
+
+// Limit returns the Selection with at most n countries, or all of them when n is 0
+func (s Selection) Limit(n int) Selection {
+	s.limit = n
+	return s
+}
+
+// Page returns the Selection with the countries of page, counting from 1, when there are size countries per page
+// A size of 0 or less means no pages, as a Limit of 0 means no limit, so the Selection has every matching country
+func (s Selection) Page(page, size int) Selection {
+	if size <= 0 {
+		s.offset, s.limit = 0, 0
+		return s
+	}
+	if page < 1 {
+		page = 1
+	}
+	s.offset, s.limit = (page-1)*size, size
+	return s
+}
+
+// Fields returns the Selection keeping only the given fields of each country and zeroing the rest, the same as the
+// Fields option of the lookups
+func (s Selection) Fields(fields ...string) Selection {
+	s.fields = append([]string(nil), fields...)
+	return s
+}
+
+// Count returns the number of matching countries, ignoring the offset and limit, e.g. to count the pages
+func (s Selection) Count() int {
+	match, count := And(s.where...), 0
+	for _, c := range s.countries {
+		if match(c) {
+			count++
+		}
+	}
+	return count
+}
+
+// Countries returns the matching countries, sorted, paginated and with only the selected fields
+// They are copies, but share their slices and maps with the countries given to Select
+func (s Selection) Countries() []Country {
+	match := And(s.where...)
+
+	var countries []Country
+	for _, c := range s.countries {
+		if match(c) {
+			countries = append(countries, c)
+		}
+	}
+
+	if len(s.by) > 0 {
+		SortCountries(countries, s.by...)
+	}
+
+	if s.offset > 0 {
+		countries = countries[min(s.offset, len(countries)):]
+	}
+	if s.limit > 0 && s.limit < len(countries) {
+		countries = countries[:s.limit]
+	}
+
+	for i := range countries {
+		filterFields(&countries[i], s.fields)
+	}
+
+	return countries
+}
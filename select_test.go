@@ -0,0 +1,132 @@
+package restcountries
+
+import (
+	"reflect"
+	"testing"
+)
+
+var selectCountries = []Country{
+	{Name: "Germany", Alpha2Code: "DE", Region: "Europe", Subregion: "Western Europe", Population: 83240525, Area: 357114, Gini: 31.9,
+		Currencies: []Currency{{Code: "EUR"}}, Languages: []Language{{Iso6391: "de", Iso6392: "deu"}}, RegionalBlocs: []RegionalBloc{{Acronym: "EU"}}},
+	{Name: "Finland", Alpha2Code: "FI", Region: "Europe", Subregion: "Northern Europe", Population: 5530719, Area: 338424, Gini: 27.1,
+		Currencies: []Currency{{Code: "EUR"}}, Languages: []Language{{Iso6391: "fi", Iso6392: "fin"}, {Iso6391: "sv", Iso6392: "swe"}}, RegionalBlocs: []RegionalBloc{{Acronym: "EU"}}},
+	{Name: "Estonia", Alpha2Code: "EE", Region: "Europe", Subregion: "Northern Europe", Population: 1331057, Area: 45227, Gini: 30.4,
+		Currencies: []Currency{{Code: "EUR"}}, Languages: []Language{{Iso6391: "et", Iso6392: "est"}}, RegionalBlocs: []RegionalBloc{{Acronym: "EU"}}},
+	{Name: "Switzerland", Alpha2Code: "CH", Region: "Europe", Subregion: "Western Europe", Population: 8636896, Area: 41284, Gini: 33.1,
+		Currencies: []Currency{{Code: "CHF"}}, Languages: []Language{{Iso6391: "de", Iso6392: "deu"}, {Iso6391: "fr", Iso6392: "fra"}}, RegionalBlocs: []RegionalBloc{{Acronym: "EFTA"}}},
+	{Name: "France", Alpha2Code: "FR", Region: "Europe", Subregion: "Western Europe", Population: 67391582, Area: 640679, Gini: 32.4,
+		Currencies: []Currency{{Code: "EUR"}}, Languages: []Language{{Iso6391: "fr", Iso6392: "fra"}}, RegionalBlocs: []RegionalBloc{{Acronym: "EU"}}},
+	{Name: "Senegal", Alpha2Code: "SN", Region: "Africa", Subregion: "Western Africa", Population: 16743930, Area: 196722, Gini: 40.3,
+		Currencies: []Currency{{Code: "XOF"}}, Languages: []Language{{Iso6391: "fr", Iso6392: "fra"}}, RegionalBlocs: []RegionalBloc{{Acronym: "AU"}}},
+	{Name: "Vatican City", Alpha2Code: "VA", Region: "Europe", Subregion: "Southern Europe", Population: 451,
+		Currencies: []Currency{{Code: "EUR"}}, Languages: []Language{{Iso6391: "la", Iso6392: "lat"}}},
+}
+
+func selectedNames(countries []Country) []string {
+	names := []string{}
+	for _, country := range countries {
+		names = append(names, country.Name)
+	}
+	return names
+}
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		predicate Predicate
+		want      []string
+	}{
+		{"region", Region("europe"), []string{"Germany", "Finland", "Estonia", "Switzerland", "France", "Vatican City"}},
+		{"subregion", Subregion("Northern Europe"), []string{"Finland", "Estonia"}},
+		{"currency", HasCurrency("xof"), []string{"Senegal"}},
+		{"language", SpeaksLanguage("fra"), []string{"Switzerland", "France", "Senegal"}},
+		{"bloc", InBloc("EFTA"), []string{"Switzerland"}},
+		{"population at least", Population(AtLeast(16743930)), []string{"Germany", "France", "Senegal"}},
+		{"population at most", Population(AtMost(1331057)), []string{"Estonia", "Vatican City"}},
+		{"area between", Area(Between(40000, 200000)), []string{"Estonia", "Switzerland", "Senegal"}},
+		// Vatican City has no area or Gini coefficient, so it is in no range
+		{"area at most", Area(AtMost(50000)), []string{"Estonia", "Switzerland"}},
+		{"density", Density(AtLeast(200)), []string{"Germany", "Switzerland"}},
+		{"gini", Gini(AtMost(30.4)), []string{"Finland", "Estonia"}},
+		{"and", And(Region("Europe"), SpeaksLanguage("de")), []string{"Germany", "Switzerland"}},
+		{"or", Or(HasCurrency("CHF"), HasCurrency("XOF")), []string{"Switzerland", "Senegal"}},
+		{"not", Not(Region("Europe")), []string{"Senegal"}},
+		{"no predicates", And(), []string{"Germany", "Finland", "Estonia", "Switzerland", "France", "Senegal", "Vatican City"}},
+		{"no alternatives", Or(), []string{}},
+	}
+
+	for _, test := range tests {
+		got := selectedNames(Select(selectCountries).Where(test.predicate).Countries())
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q; want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSelection(t *testing.T) {
+	euro := Select(selectCountries).Where(Region("Europe"), HasCurrency("EUR"))
+
+	tests := []struct {
+		name      string
+		selection Selection
+		want      []string
+		wantCount int
+	}{
+		{"where", euro, []string{"Germany", "Finland", "Estonia", "France", "Vatican City"}, 5},
+		{"where again", euro.Where(Population(AtLeast(5_000_000))), []string{"Germany", "Finland", "France"}, 3},
+		{"sort", euro.SortBy(ByArea.Desc()), []string{"France", "Germany", "Finland", "Estonia", "Vatican City"}, 5},
+		{"limit", euro.SortBy(ByName).Limit(2), []string{"Estonia", "Finland"}, 5},
+		{"offset", euro.SortBy(ByName).Offset(3), []string{"Germany", "Vatican City"}, 5},
+		{"offset past the end", euro.Offset(10), []string{}, 5},
+		{"page", euro.SortBy(ByPopulation.Desc()).Page(2, 2), []string{"Finland", "Estonia"}, 5},
+		{"last page", euro.SortBy(ByPopulation.Desc()).Page(3, 2), []string{"Vatican City"}, 5},
+		{"page before the first", euro.Page(0, 2), []string{"Germany", "Finland"}, 5},
+		{"no pages", euro.Offset(1).Limit(1).Page(2, 0), []string{"Germany", "Finland", "Estonia", "France", "Vatican City"}, 5},
+		{"negative page size", euro.Page(2, -2), []string{"Germany", "Finland", "Estonia", "France", "Vatican City"}, 5},
+	}
+
+	for _, test := range tests {
+		if got := selectedNames(test.selection.Countries()); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %q; want %q", test.name, got, test.want)
+		}
+		if got := test.selection.Count(); got != test.wantCount {
+			t.Errorf("%s: got count %d; want %d", test.name, got, test.wantCount)
+		}
+	}
+
+	// The base Selection is unchanged by the ones built on it
+	if got := euro.Count(); got != 5 {
+		t.Errorf("got count %d; want 5", got)
+	}
+}
+
+func TestSelectionFields(t *testing.T) {
+	got := Select(selectCountries).Where(InBloc("EFTA")).Fields("name", "Alpha2Code").Countries()
+	want := []Country{{Name: "Switzerland", Alpha2Code: "CH"}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v; want %+v", got, want)
+	}
+
+	// The countries given to Select keep every field
+	if selectCountries[3].Population == 0 {
+		t.Error("got the population of the original country zeroed")
+	}
+}
+
+func TestSelectionOffline(t *testing.T) {
+	countries, _ := NewOffline().All(AllOptions{})
+
+	// The snapshot has no population, see data/README.md
+	selection := Select(countries).Where(InBloc("EU"), HasCurrency("EUR"), Area(AtLeast(300_000)))
+	got := selection.SortBy(ByArea.Desc()).Fields("name", "area").Countries()
+
+	if len(got) == 0 || got[0].Name != "France" {
+		t.Fatalf("got %q; want France first", selectedNames(got))
+	}
+	for _, country := range got {
+		if country.Area < 300_000 || country.Region != "" {
+			t.Errorf("got %+v", country)
+		}
+	}
+}
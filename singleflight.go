@@ -0,0 +1,53 @@
+package restcountries
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent requests with the same key, so only the first of them goes over the network
+// The zero value is ready to use
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is a request in flight, which the callers arriving after the first one wait for
+type flightCall struct {
+	done chan struct{} // closed when resp and err are set
+	resp *response
+	err  error
+}
+
+// do calls fn for the first caller with key and makes concurrent callers with the same key wait for its result
+// leader is true for the caller which ran fn. A caller which waits stops waiting with the error of ctx when ctx is done
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*response, error)) (resp *response, leader bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.resp, false, call.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.resp, call.err = fn()
+	return call.resp, true, call.err
+}
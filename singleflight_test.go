@@ -0,0 +1,185 @@
+package restcountries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleflight(t *testing.T) {
+	var calls int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprintln(w, `[{"name":"Colombia", "capital": "Bogotá", "callingCodes": ["57"]}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	const callers = 10
+	results := make([][]Country, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = testClient.Codes(CodesOptions{Codes: []string{"CO"}})
+		}(i)
+	}
+
+	// let the other callers join the request in flight before it completes
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("got %d calls; want 1", calls)
+	}
+
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || len(results[i]) != 1 || results[i][0].Name != "Colombia" {
+			t.Fatalf("caller %d got %v, %v; want Colombia", i, results[i], errs[i])
+		}
+	}
+
+	// each caller has its own copy
+	results[0][0].Name = "Changed"
+	results[0][0].CallingCodes[0] = "0"
+	if results[1][0].Name != "Colombia" || results[1][0].CallingCodes[0] != "57" {
+		t.Fatalf("got %v; want an unchanged copy", results[1][0])
+	}
+}
+
+func TestSingleflightLeaderCancelled(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// the first request hangs until the leader gives up
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	leaderCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var leaderErr, followerErr error
+	var followerResult []Country
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, leaderErr = testClient.NameContext(leaderCtx, NameOptions{Name: "France"})
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		followerResult, followerErr = testClient.Name(NameOptions{Name: "France"})
+	}()
+	wg.Wait()
+
+	if leaderErr == nil {
+		t.Fatalf("got leader err nil; want the context error")
+	}
+
+	if followerErr != nil || len(followerResult) != 1 {
+		t.Fatalf("got follower %v, %v; want France", followerResult, followerErr)
+	}
+}
+
+func TestSingleflightFollowerCancelled(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	// the leader's request stalls until the end of the test
+	go testClient.Name(NameOptions{Name: "France"})
+	<-started
+
+	followerCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := testClient.NameContext(followerCtx, NameOptions{Name: "France"})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got err %v; want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("follower returned after %v; want soon after its deadline", elapsed)
+	}
+}
+
+func TestSingleflightCacheMode(t *testing.T) {
+	var calls int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprintln(w, `[{"name":"France", "capital": "Paris"}]`)
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache(10)
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, errs[0] = testClient.AllContext(WithCacheMode(context.Background(), CacheBypass), AllOptions{})
+	}()
+	<-started
+
+	// a caller which caches the response doesn't join the request of one which bypasses the cache
+	go func() {
+		defer wg.Done()
+		_, errs[1] = testClient.All(AllOptions{})
+	}()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("the caching caller joined the request bypassing the cache")
+	}
+
+	close(release)
+	wg.Wait()
+	if errs[0] != nil || errs[1] != nil {
+		t.Fatalf("got errs %v; want nil", errs)
+	}
+
+	if cache.Len() != 1 {
+		t.Fatalf("got %d cache entries; want 1", cache.Len())
+	}
+	if _, err := testClient.All(AllOptions{}); err != nil || calls != 2 {
+		t.Fatalf("got %d calls, err %v; want 2 calls and the cached countries", calls, err)
+	}
+}
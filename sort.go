@@ -0,0 +1,126 @@
+package restcountries
+
+import (
+	"sort"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// By is an order to sort countries in, see SortCountries
+// Names are compared with the collation rules of a language, so accented and non-Latin names sort where a reader of
+// that language expects them, rather than in byte order
+type By struct {
+	name  func(Country) string          // the name to collate, for orders by name
+	value func(Country) (float64, bool) // the value and whether it is known, for numeric orders
+	tag   language.Tag                  // the language whose collation rules compare names
+	desc  bool
+}
+
+var (
+	// ByName sorts by the English name, with the collation rules shared by most languages; see In for others
+	ByName = By{name: func(c Country) string { return c.Name }, tag: language.Und}
+
+	// ByPopulation sorts by population
+	ByPopulation = By{value: func(c Country) (float64, bool) { return float64(c.Population), true }}
+
+	// ByArea sorts by area in km², with countries of unknown area last
+	ByArea = By{value: func(c Country) (float64, bool) { return c.Area, c.Area > 0 }}
+
+	// ByDensity sorts by population per km², with countries of unknown area last
+	ByDensity = By{value: func(c Country) (float64, bool) {
+		if c.Area <= 0 {
+			return 0, false
+		}
+		return float64(c.Population) / c.Area, true
+	}}
+
+	// ByGini sorts by Gini coefficient, with countries without one last
+	ByGini = By{value: func(c Country) (float64, bool) { return c.Gini, c.Gini > 0 }}
+)
+
+// ByTranslatedName sorts by the name in the language of tag, as returned by Country.LocalizedName, with the
+// collation rules of that language
+func ByTranslatedName(tag language.Tag) By {
+	return By{name: func(c Country) string { return c.LocalizedName(tag) }, tag: tag}
+}
+
+// Desc returns the order reversed, largest or last in the alphabet first
+// Countries with an unknown value still sort last
+func (b By) Desc() By {
+	b.desc = true
+	return b
+}
+
+// In returns the order with names compared by the collation rules of the language of tag, e.g. ByName.In(language.Swedish)
+// sorts Åland Islands after Zambia, as Å is a letter after Z in Swedish. It makes no difference to numeric orders
+func (b By) In(tag language.Tag) By {
+	b.tag = tag
+	return b
+}
+
+// compare returns -1, 0 or +1 as x sorts before, with or after y, with collator comparing names
+func (b By) compare(x, y Country, collator *collate.Collator) int {
+	if b.name != nil {
+		result := collator.CompareString(b.name(x), b.name(y))
+		if b.desc {
+			return -result
+		}
+		return result
+	}
+	if b.value == nil {
+		return 0 // the zero By
+	}
+
+	vx, xKnown := b.value(x)
+	vy, yKnown := b.value(y)
+	switch {
+	case !xKnown || !yKnown:
+		return compareBool(yKnown, xKnown)
+	case vx < vy && !b.desc, vx > vy && b.desc:
+		return -1
+	case vx > vy && !b.desc, vx < vy && b.desc:
+		return 1
+	}
+	return 0
+}
+
+// compareBool orders false before true
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case b:
+		return -1
+	}
+	return 1
+}
+
+// SortCountries sorts countries in place by the given orders, each breaking the ties of the ones before it, e.g.
+//
+//	SortCountries(countries, ByGini.Desc(), ByName)
+//
+// The sort is stable, so countries which tie on every order keep their original order. Without any order the
+// countries are sorted by name
+func SortCountries(countries []Country, by ...By) {
+	if len(by) == 0 {
+		by = []By{ByName}
+	}
+
+	// A Collator isn't safe for concurrent use, so every sort has its own
+	collators := make([]*collate.Collator, len(by))
+	for i, b := range by {
+		if b.name != nil {
+			collators[i] = collate.New(b.tag)
+		}
+	}
+
+	sort.SliceStable(countries, func(i, j int) bool {
+		for k, b := range by {
+			if result := b.compare(countries[i], countries[j], collators[k]); result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+}
@@ -0,0 +1,90 @@
+package restcountries
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+var sortCountries = []Country{
+	{Name: "Zambia", Population: 18383956, Area: 752612, Gini: 57.1, Translations: Translations{"ja": "ザンビア", "de": "Sambia"}},
+	{Name: "Åland Islands", Population: 28875, Area: 1580, Translations: Translations{"ja": "オーランド諸島", "de": "Åland"}},
+	{Name: "Côte d'Ivoire", Population: 26378275, Area: 322463, Gini: 41.5, Translations: Translations{"ja": "コートジボワール", "de": "Elfenbeinküste"}},
+	{Name: "Austria", Population: 8917205, Area: 83871, Gini: 29.7, Translations: Translations{"ja": "オーストリア", "de": "Österreich"}},
+	{Name: "Colombia", Population: 50882884, Area: 1141748, Gini: 51.3, Translations: Translations{"ja": "コロンビア", "de": "Kolumbien"}},
+	{Name: "Antarctica", Population: 1000, Translations: Translations{"ja": "南極大陸", "de": "Antarktika"}},
+}
+
+func sortedNames(by ...By) []string {
+	countries := append([]Country(nil), sortCountries...)
+	SortCountries(countries, by...)
+
+	names := make([]string, len(countries))
+	for i, country := range countries {
+		names[i] = country.Name
+	}
+	return names
+}
+
+func TestSortCountries(t *testing.T) {
+	tests := []struct {
+		name string
+		by   []By
+		want []string
+	}{
+		{"default", nil, []string{"Åland Islands", "Antarctica", "Austria", "Colombia", "Côte d'Ivoire", "Zambia"}},
+		{"name", []By{ByName}, []string{"Åland Islands", "Antarctica", "Austria", "Colombia", "Côte d'Ivoire", "Zambia"}},
+		{"name desc", []By{ByName.Desc()}, []string{"Zambia", "Côte d'Ivoire", "Colombia", "Austria", "Antarctica", "Åland Islands"}},
+		// Å is a letter after Z in Swedish
+		{"name in Swedish", []By{ByName.In(language.Swedish)}, []string{"Antarctica", "Austria", "Colombia", "Côte d'Ivoire", "Zambia", "Åland Islands"}},
+		// Elfenbeinküste, Kolumbien, Österreich sorts with O, Sambia
+		{"translated name", []By{ByTranslatedName(language.German)}, []string{"Åland Islands", "Antarctica", "Côte d'Ivoire", "Colombia", "Austria", "Zambia"}},
+		// Katakana in gojūon order, then kanji
+		{"translated name in Japanese", []By{ByTranslatedName(language.Japanese)}, []string{"Austria", "Åland Islands", "Côte d'Ivoire", "Colombia", "Zambia", "Antarctica"}},
+		{"population", []By{ByPopulation}, []string{"Antarctica", "Åland Islands", "Austria", "Zambia", "Côte d'Ivoire", "Colombia"}},
+		{"population desc", []By{ByPopulation.Desc()}, []string{"Colombia", "Côte d'Ivoire", "Zambia", "Austria", "Åland Islands", "Antarctica"}},
+		// Antarctica has no area, so it is last either way
+		{"area", []By{ByArea}, []string{"Åland Islands", "Austria", "Côte d'Ivoire", "Zambia", "Colombia", "Antarctica"}},
+		{"area desc", []By{ByArea.Desc()}, []string{"Colombia", "Zambia", "Côte d'Ivoire", "Austria", "Åland Islands", "Antarctica"}},
+		{"density", []By{ByDensity.Desc()}, []string{"Austria", "Côte d'Ivoire", "Colombia", "Zambia", "Åland Islands", "Antarctica"}},
+		{"gini then name", []By{ByGini, ByName}, []string{"Austria", "Côte d'Ivoire", "Colombia", "Zambia", "Åland Islands", "Antarctica"}},
+		{"gini desc then name desc", []By{ByGini.Desc(), ByName.Desc()}, []string{"Zambia", "Colombia", "Côte d'Ivoire", "Austria", "Antarctica", "Åland Islands"}},
+	}
+
+	for _, test := range tests {
+		got := sortedNames(test.by...)
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: got %q; want %q", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSortCountriesStable(t *testing.T) {
+	// Without a Gini coefficient, Åland Islands and Antarctica tie and keep their order
+	got := sortedNames(ByGini)
+	want := []string{"Austria", "Côte d'Ivoire", "Colombia", "Zambia", "Åland Islands", "Antarctica"}
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("got %q; want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestSortCountriesOffline(t *testing.T) {
+	countries, _ := NewOffline().All(AllOptions{})
+	SortCountries(countries, ByName)
+
+	for i := 1; i < len(countries); i++ {
+		if countries[i-1].Name == "Zimbabwe" {
+			t.Errorf("got %q after Zimbabwe", countries[i].Name)
+		}
+	}
+	if countries[0].Name != "Afghanistan" {
+		t.Errorf("got first %q; want Afghanistan", countries[0].Name)
+	}
+}
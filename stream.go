@@ -0,0 +1,142 @@
+package restcountries
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// StreamingProvider is a Provider which can also decode a response one country at a time, so LookupIter can stream it
+type StreamingProvider interface {
+	Provider
+	// DecodeNext decodes the next country of the array of countries which dec is reading
+	DecodeNext(dec *json.Decoder) (Country, error)
+}
+
+func (countrylayer) DecodeNext(dec *json.Decoder) (Country, error) {
+	var country Country
+	err := dec.Decode(&country)
+	return country, err
+}
+
+func (restCountriesV3) DecodeNext(dec *json.Decoder) (Country, error) {
+	var country CountryV3
+	if err := dec.Decode(&country); err != nil {
+		return Country{}, err
+	}
+	return country.Country(), nil
+}
+
+// AllIter is like AllContext but yields the countries one at a time, decoding them from the response as it arrives
+// so the whole response is never held in memory, see LookupIter
+func (r *RestCountries) AllIter(ctx context.Context, options AllOptions) iter.Seq2[Country, error] {
+	return r.LookupIter(ctx, Request{Endpoint: EndpointAll, Fields: options.Fields})
+}
+
+// LookupIter is like Lookup but yields the countries one at a time. An error is yielded last, with a zero Country
+// The countries are decoded from the response as it arrives, when the provider is a StreamingProvider and the client has
+// no backend, failover or cache, or the cache is bypassed with WithCacheMode. Otherwise LookupIter yields the result of Lookup
+// Breaking out of the loop closes the response
+func (r *RestCountries) LookupIter(ctx context.Context, req Request) iter.Seq2[Country, error] {
+	return func(yield func(Country, error) bool) {
+		provider, ok := r.provider.(StreamingProvider)
+		if !ok || r.backend != nil || r.failover != nil || (r.cache != nil && cacheModeFrom(ctx) != CacheBypass) {
+			countries, err := r.Lookup(ctx, req)
+			yieldAll(countries, err, yield)
+			return
+		}
+
+		if err := req.validate(); err != nil {
+			yield(Country{}, err)
+			return
+		}
+
+		ctx, end := r.startLookup(ctx, req)
+		results := 0
+		var lookupErr error
+		r.stream(ctx, req, provider, func(country Country, err error) bool {
+			if err != nil {
+				lookupErr = err
+			} else {
+				results++
+			}
+			return yield(country, err)
+		})
+		end(results, lookupErr)
+	}
+}
+
+// stream answers a validated req like LookupIter, decoding the countries from the response as it arrives
+func (r *RestCountries) stream(ctx context.Context, req Request, provider StreamingProvider, yield func(Country, error) bool) {
+	url, err := provider.URL(r.apiRoot, r.urlAPIKey(), req)
+	if err != nil {
+		yield(Country{}, err)
+		return
+	}
+
+	resp, err := r.get(ctx, url, req, nil, true)
+	if err != nil {
+		yield(Country{}, redactKey(err, r.apiKey))
+		return
+	}
+	defer resp.body.Close()
+
+	body := bufio.NewReader(resp.body)
+	if resp.status >= http.StatusMultipleChoices || peekJSONByte(body) != '[' {
+		// an error body is small, so it is read and decoded like any other response
+		resp.content, err = io.ReadAll(body)
+		if err != nil {
+			yield(Country{}, redactKey(err, r.apiKey))
+			return
+		}
+		countries, err := r.decodeCountries(resp, url, req)
+		yieldAll(countries, redactKey(err, r.apiKey), yield)
+		return
+	}
+
+	dec := json.NewDecoder(body)
+	if _, err := dec.Token(); err != nil {
+		yield(Country{}, redactKey(err, r.apiKey))
+		return
+	}
+	for dec.More() {
+		country, err := provider.DecodeNext(dec)
+		if err != nil {
+			yield(Country{}, redactKey(err, r.apiKey))
+			return
+		}
+		if !yield(country, nil) {
+			return
+		}
+	}
+}
+
+// yieldAll yields countries, then err if it isn't nil
+func yieldAll(countries []Country, err error, yield func(Country, error) bool) {
+	if err != nil {
+		yield(Country{}, err)
+		return
+	}
+	for _, country := range countries {
+		if !yield(country, nil) {
+			return
+		}
+	}
+}
+
+// peekJSONByte returns the first byte of r which isn't JSON whitespace without consuming it, or 0 at the end or on an error
+func peekJSONByte(r *bufio.Reader) byte {
+	for {
+		c, err := r.Peek(1)
+		if err != nil {
+			return 0
+		}
+		if firstJSONByte(c) != 0 {
+			return c[0]
+		}
+		r.Discard(1)
+	}
+}
@@ -0,0 +1,287 @@
+package restcountries
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAllIter(t *testing.T) {
+	var gotURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURL = r.URL.String()
+		fmt.Fprint(w, " \n[")
+		for i := 0; i < 1000; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"name":"Country %d", "alpha2Code": "C%d"}`, i, i)
+		}
+		fmt.Fprint(w, "]")
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL))
+
+	var names []string
+	for country, err := range testClient.AllIter(context.Background(), AllOptions{Fields: []string{"Name"}}) {
+		if err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+		names = append(names, country.Name)
+	}
+
+	if len(names) != 1000 || names[0] != "Country 0" || names[999] != "Country 999" {
+		t.Fatalf("got %d countries; want 1000 in order", len(names))
+	}
+	if wantURL := "/all?access_key=TEST_API_KEY&fields=name%3B"; gotURL != wantURL {
+		t.Errorf("got url %q; want %q", gotURL, wantURL)
+	}
+
+	// breaking out of the loop stops decoding
+	count := 0
+	for range testClient.AllIter(context.Background(), AllOptions{}) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("got %d countries; want 3", count)
+	}
+}
+
+func TestLookupIterErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/name/Atlantis":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"status": 404, "message": "Not Found"}`)
+		case "/name/Broken":
+			fmt.Fprint(w, `[{"name":"Estonia"}, {"name": 42}]`)
+		default:
+			fmt.Fprintln(w, `{"success": false, "error": {"code": 101, "type": "invalid_access_key", "info": "Invalid key"}}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		options   []Option
+		req       Request
+		wantNames []string
+		wantErr   error
+	}{
+		{"not found", nil, Request{Endpoint: EndpointName, Term: "Atlantis"}, nil, nil},
+		{"not found error", []Option{WithNotFoundError()}, Request{Endpoint: EndpointName, Term: "Atlantis"}, nil, ErrNotFound},
+		{"error body", nil, Request{Endpoint: EndpointAll}, nil, ErrInvalidAPIKey},
+		{"empty search term", nil, Request{Endpoint: EndpointName}, nil, ErrEmptySearchTerm},
+		{"bad country", nil, Request{Endpoint: EndpointName, Term: "Broken"}, []string{"Estonia"}, errors.New("json")},
+	}
+
+	for _, test := range tests {
+		testClient := New("TEST_API_KEY", append(test.options, WithBaseURL(server.URL))...)
+
+		var names []string
+		var gotErr error
+		for country, err := range testClient.LookupIter(context.Background(), test.req) {
+			if err != nil {
+				gotErr = err
+				continue
+			}
+			names = append(names, country.Name)
+		}
+
+		if strings.Join(names, ",") != strings.Join(test.wantNames, ",") {
+			t.Errorf("%s: got %v; want %v", test.name, names, test.wantNames)
+		}
+		switch {
+		case test.wantErr == nil && gotErr != nil:
+			t.Errorf("%s: got err %v; want nil", test.name, gotErr)
+		case test.wantErr != nil && gotErr == nil:
+			t.Errorf("%s: got no err; want %v", test.name, test.wantErr)
+		case test.wantErr != nil && test.wantErr.Error() != "json" && !errors.Is(gotErr, test.wantErr):
+			t.Errorf("%s: got err %v; want %v", test.name, gotErr, test.wantErr)
+		}
+	}
+}
+
+func TestLookupIterRetry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, `{"status": 503, "message": "Service Unavailable"}`)
+			return
+		}
+		fmt.Fprintln(w, `[{"name":"Estonia"}]`)
+	}))
+	defer server.Close()
+
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithTimeout(time.Second),
+		WithRetry(RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond}))
+
+	var names []string
+	for country, err := range testClient.AllIter(context.Background(), AllOptions{}) {
+		if err != nil {
+			t.Fatalf("got err %v; want nil", err)
+		}
+		names = append(names, country.Name)
+	}
+
+	if len(names) != 1 || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("got %v after %d calls; want Estonia after 2", names, calls)
+	}
+}
+
+func TestLookupIterV3(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, restCountriesV3Estonia)
+	}))
+	defer server.Close()
+
+	testClient := New("", WithProvider(RestCountriesV3), WithBaseURL(server.URL))
+
+	for country, err := range testClient.LookupIter(context.Background(), Request{Endpoint: EndpointCodes, Codes: []string{"EE"}}) {
+		if err != nil || country.Name != "Estonia" || country.CallingCodes[0] != "372" {
+			t.Fatalf("got %v, %v; want Estonia", country.Name, err)
+		}
+	}
+}
+
+func TestLookupIterNotStreamed(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprintln(w, `[{"name":"Estonia"}, {"name":"Latvia"}]`)
+	}))
+	defer server.Close()
+
+	// with a cache, the iterator yields the result of Lookup, which is cached
+	testClient := New("TEST_API_KEY", WithBaseURL(server.URL), WithCache(NewMemoryCache(10), time.Minute))
+	for i := 0; i < 2; i++ {
+		count := 0
+		for _, err := range testClient.AllIter(context.Background(), AllOptions{}) {
+			if err != nil {
+				t.Fatalf("got err %v; want nil", err)
+			}
+			count++
+		}
+		if count != 2 {
+			t.Fatalf("got %d countries; want 2", count)
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("got %d calls; want 1", got)
+	}
+
+	offline := New("", WithBackend(NewOffline()))
+	count := 0
+	for range offline.AllIter(context.Background(), AllOptions{}) {
+		count++
+	}
+	if count != len(NewOffline().countries) {
+		t.Errorf("got %d countries; want all of the snapshot", count)
+	}
+}
+
+func TestLookupDecodesFromBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/name/Truncated":
+			fmt.Fprint(w, `[{"name":"Estonia"}, {"name":`)
+		case "/name/Atlantis":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"status": 404, "message": "Not Found"}`)
+		default:
+			fmt.Fprint(w, `[{"name":"Estonia"}, {"name":"Latvia"}]`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		options  []Option
+		streamed bool
+	}{
+		{"no cache", nil, true},
+		{"cache", []Option{WithCache(NewMemoryCache(10), time.Minute)}, false},
+		{"response hook", []Option{WithOnResponse(func(context.Context, ResponseInfo) {})}, false},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		testClient := New("TEST_API_KEY", append(test.options, WithBaseURL(server.URL), WithLogger(logger))...)
+
+		result, err := testClient.Name(NameOptions{Name: "Estonia"})
+		if err != nil || len(result) != 2 || result[1].Name != "Latvia" {
+			t.Fatalf("%s: got %v, %v; want Estonia and Latvia", test.name, result, err)
+		}
+		if got := strings.Contains(buf.String(), "streamed=true"); got != test.streamed {
+			t.Errorf("%s: got streamed %v; want %v in logs %q", test.name, got, test.streamed, buf.String())
+		}
+
+		result, err = testClient.Name(NameOptions{Name: "Atlantis"})
+		if err != nil || len(result) != 0 {
+			t.Errorf("%s: got %v, %v; want not found", test.name, result, err)
+		}
+
+		if _, err := testClient.Name(NameOptions{Name: "Truncated"}); err == nil {
+			t.Errorf("%s: got err nil for a truncated body; want an error", test.name)
+		}
+	}
+}
+
+func TestCloneCountries(t *testing.T) {
+	countries := []Country{{
+		Name:          "Estonia",
+		CallingCodes:  []string{"372"},
+		Translations:  Translations{"de": "Estland"},
+		RegionalBlocs: []RegionalBloc{{Acronym: "EU", OtherNames: []string{"Union européenne"}}},
+	}}
+
+	clones := cloneCountries(countries)
+	clones[0].CallingCodes[0] = "0"
+	clones[0].Translations["de"] = "Changed"
+	clones[0].RegionalBlocs[0].OtherNames[0] = "Changed"
+
+	if countries[0].CallingCodes[0] != "372" || countries[0].Translations["de"] != "Estland" ||
+		countries[0].RegionalBlocs[0].OtherNames[0] != "Union européenne" {
+		t.Errorf("got %+v; want the original unchanged", countries[0])
+	}
+	if clones[0].Borders != nil {
+		t.Errorf("got borders %v; want nil", clones[0].Borders)
+	}
+}
+
+func TestPeekJSONByte(t *testing.T) {
+	tests := []struct {
+		input string
+		want  byte
+	}{
+		{"[]", '['},
+		{" \r\n\t{}", '{'},
+		{"   ", 0},
+		{"", 0},
+	}
+
+	for _, test := range tests {
+		r := bufio.NewReader(strings.NewReader(test.input))
+		if got := peekJSONByte(r); got != test.want {
+			t.Errorf("%q: got %q; want %q", test.input, got, test.want)
+		}
+		if got := firstJSONByte([]byte(test.input)); got != test.want {
+			t.Errorf("%q: got %q from firstJSONByte; want %q", test.input, got, test.want)
+		}
+	}
+}